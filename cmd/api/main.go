@@ -13,6 +13,8 @@ import (
 	"github.com/Alfian57/belajar-golang/internal/config"
 	"github.com/Alfian57/belajar-golang/internal/cron"
 	"github.com/Alfian57/belajar-golang/internal/database"
+	"github.com/Alfian57/belajar-golang/internal/geoip"
+	"github.com/Alfian57/belajar-golang/internal/ingest"
 	"github.com/Alfian57/belajar-golang/internal/logger"
 	"github.com/Alfian57/belajar-golang/internal/middleware"
 	"github.com/Alfian57/belajar-golang/internal/router"
@@ -32,6 +34,8 @@ func main() {
 	logger.Init()
 	database.Init(cfg.Database)
 	validation.Init()
+	geoip.Init(cfg.GeoIP)
+	ingest.Init(cfg.Ingest, cfg.Visitor)
 	cron.Init()
 
 	if err := os.MkdirAll("logs", 0755); err != nil {
@@ -97,6 +101,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Store the visits that are still buffered now that no more requests are served
+	if err := ingest.Shutdown(ctx); err != nil {
+		logger.Log.Errorf("Visit pipeline failed to drain: %v", err)
+		os.Exit(1)
+	}
+
 	logger.Log.Info("Server gracefully stopped")
 	os.Exit(0)
 }
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/Alfian57/belajar-golang/internal/config"
+	"github.com/Alfian57/belajar-golang/internal/database"
+	"github.com/Alfian57/belajar-golang/internal/di"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/service"
+	"github.com/Alfian57/belajar-golang/internal/utils/blocklist"
+)
+
+func main() {
+	// Command line flags
+	var (
+		filePath = flag.String("file", "", "Path to the blocklist file to import")
+		format   = flag.String("format", blocklist.FormatAuto, "Blocklist format: auto, hosts, plain or csv")
+	)
+	flag.Parse()
+
+	if *filePath == "" {
+		fmt.Fprintln(os.Stderr, "Usage: blocklist -file=<path> [-format=auto|hosts|plain|csv]")
+		os.Exit(2)
+	}
+
+	// Load environment variables
+	config.LoadEnv()
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to load config: %v", err))
+	}
+
+	// Initialize logger and database
+	logger.Init()
+	database.Init(cfg.Database)
+
+	file, err := os.Open(*filePath)
+	if err != nil {
+		logger.Log.Fatalf("Failed to open blocklist: %v", err)
+	}
+	defer file.Close()
+
+	bannedDomainService := di.InitializeBannedDomainService()
+
+	result, err := bannedDomainService.ImportBannedDomains(context.Background(), file, *format)
+	if err != nil {
+		logger.Log.Fatalf("Blocklist import failed: %v", err)
+	}
+
+	logger.Log.Infof("Blocklist import completed: %d added, %d skipped, %d invalid", result.Added, result.Skipped, result.Invalid)
+
+	// The scan of existing links runs in the background and would be killed on exit
+	if result.Added > 0 {
+		logger.Log.Infoln("Waiting for the scan of existing links to finish")
+		service.WaitForBannedDomainScans()
+		logger.Log.Infoln("Scan of existing links finished")
+	}
+}
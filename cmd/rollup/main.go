@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/config"
+	"github.com/Alfian57/belajar-golang/internal/database"
+	"github.com/Alfian57/belajar-golang/internal/di"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+)
+
+func main() {
+	// Command line flags
+	var (
+		fromDate = flag.String("from", "", "First UTC day to roll up (YYYY-MM-DD)")
+		toDate   = flag.String("to", "", "Last UTC day to roll up (YYYY-MM-DD), defaults to yesterday")
+	)
+	flag.Parse()
+
+	if *fromDate == "" {
+		fmt.Fprintln(os.Stderr, "Usage: rollup -from=<YYYY-MM-DD> [-to=<YYYY-MM-DD>]")
+		os.Exit(2)
+	}
+
+	from, err := time.Parse(time.DateOnly, *fromDate)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid from date: %v\n", err)
+		os.Exit(2)
+	}
+
+	to := time.Now().UTC().AddDate(0, 0, -1)
+	if *toDate != "" {
+		to, err = time.Parse(time.DateOnly, *toDate)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Invalid to date: %v\n", err)
+			os.Exit(2)
+		}
+	}
+
+	// Load environment variables
+	config.LoadEnv()
+
+	// Load configuration
+	cfg, err := config.Load()
+	if err != nil {
+		panic(fmt.Sprintf("Failed to load config: %v", err))
+	}
+
+	// Initialize logger and database
+	logger.Init()
+	database.Init(cfg.Database)
+
+	statsRollupService := di.InitializeStatsRollupService()
+
+	result, err := statsRollupService.Backfill(context.Background(), from, to)
+	if err != nil {
+		logger.Log.Fatalf("Stats rollup backfill failed: %v", err)
+	}
+
+	logger.Log.Infof("Stats rollup backfill completed: %d days, %d visits", result.Days, result.Visits)
+}
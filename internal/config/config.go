@@ -1,9 +1,25 @@
 package config
 
+import (
+	"errors"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/utils/ipanon"
+)
+
+// Cfg holds the configuration loaded by Load so that it can be read by
+// components that are constructed through dependency injection.
+var Cfg *Config
+
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Cors     CorsConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	Cors      CorsConfig
+	Url       UrlConfig
+	Retention RetentionConfig
+	Visitor   VisitorConfig
+	GeoIP     GeoIPConfig
+	Ingest    IngestConfig
 }
 
 type ServerConfig struct {
@@ -25,6 +41,41 @@ type CorsConfig struct {
 	AllowCredentials bool     `env:"CORS_ALLOW_CREDENTIALS" envDefault:"true"`
 }
 
+type UrlConfig struct {
+	ShortUrlLength     int      `env:"SHORT_URL_LENGTH" envDefault:"7"`
+	ShortUrlMaxRetries int      `env:"SHORT_URL_MAX_RETRIES" envDefault:"5"`
+	ReservedShortUrls  []string `env:"RESERVED_SHORT_URLS" envDefault:"api,admin,login,logout,register,refresh,me,static,assets,health"`
+
+	AccessSecret          string        `env:"URL_ACCESS_SECRET"`
+	AccessTTL             time.Duration `env:"URL_ACCESS_TTL" envDefault:"10m"`
+	PasswordMaxAttempts   int           `env:"URL_PASSWORD_MAX_ATTEMPTS" envDefault:"5"`
+	PasswordAttemptWindow time.Duration `env:"URL_PASSWORD_ATTEMPT_WINDOW" envDefault:"15m"`
+}
+
+type RetentionConfig struct {
+	GracePeriod time.Duration `env:"RETENTION_GRACE_PERIOD" envDefault:"720h"`
+	BatchSize   int           `env:"RETENTION_BATCH_SIZE" envDefault:"500"`
+	Mode        string        `env:"RETENTION_MODE" envDefault:"delete"`
+}
+
+type VisitorConfig struct {
+	IpMode       string `env:"VISITOR_IP_MODE" envDefault:"truncate"`
+	IpHashSecret string `env:"VISITOR_IP_HASH_SECRET" envDefault:""`
+}
+
+type GeoIPConfig struct {
+	DatabasePath   string        `env:"GEOIP_DATABASE_PATH" envDefault:""`
+	ReloadInterval time.Duration `env:"GEOIP_RELOAD_INTERVAL" envDefault:"1m"`
+}
+
+type IngestConfig struct {
+	BufferSize    int           `env:"INGEST_BUFFER_SIZE" envDefault:"10000"`
+	Workers       int           `env:"INGEST_WORKERS" envDefault:"2"`
+	FlushSize     int           `env:"INGEST_FLUSH_SIZE" envDefault:"500"`
+	FlushInterval time.Duration `env:"INGEST_FLUSH_INTERVAL" envDefault:"1s"`
+	Policy        string        `env:"INGEST_POLICY" envDefault:"drop"`
+}
+
 func Load() (*Config, error) {
 	cfg := &Config{
 		Server: ServerConfig{
@@ -43,7 +94,66 @@ func Load() (*Config, error) {
 			AllowMethods:     GetEnvSlice("CORS_ALLOW_METHODS", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}),
 			AllowCredentials: GetEnvBool("CORS_ALLOW_CREDENTIALS", true),
 		},
+		Url: UrlConfig{
+			ShortUrlLength:     GetEnvInt("SHORT_URL_LENGTH", 7),
+			ShortUrlMaxRetries: GetEnvInt("SHORT_URL_MAX_RETRIES", 5),
+			ReservedShortUrls:  GetEnvSlice("RESERVED_SHORT_URLS", []string{"api", "admin", "login", "logout", "register", "refresh", "me", "static", "assets", "health"}),
+
+			AccessSecret:          GetEnv("URL_ACCESS_SECRET", ""),
+			AccessTTL:             GetEnvDuration("URL_ACCESS_TTL", 10*time.Minute),
+			PasswordMaxAttempts:   GetEnvInt("URL_PASSWORD_MAX_ATTEMPTS", 5),
+			PasswordAttemptWindow: GetEnvDuration("URL_PASSWORD_ATTEMPT_WINDOW", 15*time.Minute),
+		},
+		Retention: RetentionConfig{
+			GracePeriod: GetEnvDuration("RETENTION_GRACE_PERIOD", 720*time.Hour),
+			BatchSize:   GetEnvInt("RETENTION_BATCH_SIZE", 500),
+			Mode:        GetEnv("RETENTION_MODE", "delete"),
+		},
+		Visitor: VisitorConfig{
+			IpMode:       GetEnv("VISITOR_IP_MODE", "truncate"),
+			IpHashSecret: GetEnv("VISITOR_IP_HASH_SECRET", ""),
+		},
+		GeoIP: GeoIPConfig{
+			DatabasePath:   GetEnv("GEOIP_DATABASE_PATH", ""),
+			ReloadInterval: GetEnvDuration("GEOIP_RELOAD_INTERVAL", time.Minute),
+		},
+		Ingest: IngestConfig{
+			BufferSize:    GetEnvInt("INGEST_BUFFER_SIZE", 10000),
+			Workers:       GetEnvInt("INGEST_WORKERS", 2),
+			FlushSize:     GetEnvInt("INGEST_FLUSH_SIZE", 500),
+			FlushInterval: GetEnvDuration("INGEST_FLUSH_INTERVAL", time.Second),
+			Policy:        GetEnv("INGEST_POLICY", "drop"),
+		},
 	}
 
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	Cfg = cfg
+
 	return cfg, nil
 }
+
+// weakSecrets are the old defaults and the placeholders of .env.example, which are public.
+var weakSecrets = map[string]bool{
+	"":                            true,
+	"secret":                      true,
+	"your_url_access_secret":      true,
+	"your_visitor_ip_hash_secret": true,
+}
+
+// validate rejects settings that would silently defeat a security feature.
+func (c *Config) validate() error {
+	// Anyone who knows the key could sign access cookies of password protected urls
+	if weakSecrets[c.Url.AccessSecret] {
+		return errors.New("URL_ACCESS_SECRET must be set to a random value")
+	}
+
+	// A public key lets anyone reverse the hash of an IPv4 address by trying all of them
+	if c.Visitor.IpMode == ipanon.ModeHash && weakSecrets[c.Visitor.IpHashSecret] {
+		return errors.New("VISITOR_IP_HASH_SECRET must be set to a random value when VISITOR_IP_MODE is hash")
+	}
+
+	return nil
+}
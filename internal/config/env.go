@@ -5,6 +5,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -55,3 +56,16 @@ func GetEnvBool(key string, fallback bool) bool {
 	}
 	return false
 }
+
+func GetEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	durationValue, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Error converting environment variable %s to duration: %v. Using fallback value: %s", key, err, fallback)
+		return fallback
+	}
+	return durationValue
+}
@@ -31,6 +31,53 @@ const (
 	DefaultOrderBy   = "created_at"
 )
 
+// Banned Domain Constants
+const (
+	BannedDomainImportBatchSize = 500
+	MaxBannedDomainLength       = 255
+	BannedDomainScanBatchSize   = 500
+	BannedDomainScanTimeout     = 30 * time.Minute
+)
+
+// Stats Constants
+const (
+	DefaultStatsInterval = "day"
+	DefaultStatsTimezone = "UTC"
+	MaxTimeseriesBuckets = 1000
+	DefaultStatsRange    = 30 * 24 * time.Hour
+	DefaultBreakdownSize = 10
+	DefaultExportFormat  = "csv"
+	ExportTimeout        = 10 * time.Minute
+	ExportFlushSize      = 500
+)
+
+// Retention Constants
+const (
+	RetentionRunTimeout = 30 * time.Minute
+)
+
+// Stats Rollup Constants
+const (
+	RollupRunTimeout = 30 * time.Minute
+)
+
+// Ingest Constants
+const (
+	IngestFlushTimeout = 30 * time.Second
+)
+
+// Session Constants
+const (
+	RefreshTokenLifetime  = 7 * 24 * time.Hour
+	SessionCleanupTimeout = 5 * time.Minute
+)
+
+// API Key Constants
+const (
+	MaxApiKeysPerUser      = 25
+	ApiKeyLastUsedInterval = time.Minute
+)
+
 // User Constants
 const (
 	DefaultPassword = "password"
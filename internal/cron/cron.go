@@ -18,7 +18,9 @@ func Init() {
 	}
 
 	cronjobs := []Crobjob{
-		NewDeleteUrlCron(s),
+		NewUrlRetentionCron(s),
+		NewUrlStatsRollupCron(s),
+		NewSessionCleanupCron(s),
 	}
 
 	for _, job := range cronjobs {
@@ -0,0 +1,69 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/constants"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/repository"
+	"github.com/go-co-op/gocron/v2"
+)
+
+// SessionCleanupCron removes expired sessions and refresh tokens. Rotated tokens are kept until
+// they expire to detect their reuse, so without it the table keeps growing with every refresh.
+type SessionCleanupCron struct {
+	scheduler              gocron.Scheduler
+	sessionRepository      *repository.SessionRepository
+	refreshTokenRepository *repository.RefreshTokenRepository
+}
+
+func NewSessionCleanupCron(scheduler gocron.Scheduler) *SessionCleanupCron {
+	return &SessionCleanupCron{
+		scheduler:              scheduler,
+		sessionRepository:      repository.NewSessionRepository(),
+		refreshTokenRepository: repository.NewRefreshTokenRepository(),
+	}
+}
+
+func (c *SessionCleanupCron) Start(ctx context.Context) error {
+	_, err := c.scheduler.NewJob(
+		gocron.DurationJob(time.Hour),
+		gocron.NewTask(
+			func() {
+				// Every run gets its own deadline instead of sharing the startup context
+				runCtx, cancel := context.WithTimeout(ctx, constants.SessionCleanupTimeout)
+				defer cancel()
+
+				c.run(runCtx)
+			},
+		),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create session cleanup cron job: %w", err)
+	}
+
+	return nil
+}
+
+func (c *SessionCleanupCron) run(ctx context.Context) {
+	now := time.Now()
+
+	// Deleting a session also deletes its refresh tokens
+	sessions, err := c.sessionRepository.DeleteExpired(ctx, now)
+	if err != nil {
+		logger.Log.Errorw("Session Cleanup: failed to delete expired sessions", "error", err)
+		return
+	}
+
+	tokens, err := c.refreshTokenRepository.DeleteExpired(ctx, now)
+	if err != nil {
+		logger.Log.Errorw("Session Cleanup: failed to delete expired refresh tokens", "error", err)
+		return
+	}
+
+	logger.Log.Infow("Session Cleanup: expired sessions deleted", "sessions", sessions, "refresh_tokens", tokens)
+}
@@ -0,0 +1,129 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/config"
+	"github.com/Alfian57/belajar-golang/internal/constants"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/Alfian57/belajar-golang/internal/repository"
+	"github.com/go-co-op/gocron/v2"
+)
+
+// UrlRetentionCron removes urls that expired longer than the grace period ago.
+// Depending on the configured mode the urls are either deleted or archived.
+type UrlRetentionCron struct {
+	scheduler              gocron.Scheduler
+	urlRepository          *repository.UrlRepository
+	retentionRunRepository *repository.RetentionRunRepository
+	retentionConfig        config.RetentionConfig
+}
+
+func NewUrlRetentionCron(scheduler gocron.Scheduler) *UrlRetentionCron {
+	return &UrlRetentionCron{
+		scheduler:              scheduler,
+		urlRepository:          repository.NewUrlRepository(),
+		retentionRunRepository: repository.NewRetentionRunRepository(),
+		retentionConfig:        config.Cfg.Retention,
+	}
+}
+
+func (c *UrlRetentionCron) Start(ctx context.Context) error {
+	mode := c.retentionConfig.Mode
+	if mode != model.RetentionModeDelete && mode != model.RetentionModeArchive {
+		return fmt.Errorf("invalid retention mode %q", mode)
+	}
+	if c.retentionConfig.BatchSize <= 0 {
+		return fmt.Errorf("invalid retention batch size %d", c.retentionConfig.BatchSize)
+	}
+
+	_, err := c.scheduler.NewJob(
+		gocron.DailyJob(
+			1, // Every 1 day
+			gocron.NewAtTimes(
+				gocron.NewAtTime(0, 0, 0), // At midnight
+			),
+		),
+		gocron.NewTask(
+			func() {
+				// Every run gets its own deadline instead of sharing the startup context
+				runCtx, cancel := context.WithTimeout(ctx, constants.RetentionRunTimeout)
+				defer cancel()
+
+				c.run(runCtx)
+			},
+		),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create url retention cron job: %w", err)
+	}
+
+	return nil
+}
+
+func (c *UrlRetentionCron) run(ctx context.Context) {
+	run := model.RetentionRun{
+		Mode:      c.retentionConfig.Mode,
+		Status:    model.RetentionRunStatusRunning,
+		Cutoff:    time.Now().Add(-c.retentionConfig.GracePeriod),
+		StartedAt: time.Now(),
+	}
+
+	if err := c.retentionRunRepository.Create(ctx, &run); err != nil {
+		logger.Log.Errorw("URL Retention: failed to create retention run", "error", err)
+		return
+	}
+
+	// Process expired urls in bounded batches until a batch comes back short
+	var runErr error
+	for {
+		processed, err := c.processBatch(ctx, run.Cutoff)
+		if err != nil {
+			runErr = err
+			break
+		}
+
+		run.BatchCount++
+		run.ProcessedCount += int(processed)
+
+		if processed < int64(c.retentionConfig.BatchSize) {
+			break
+		}
+	}
+
+	c.finishRun(&run, runErr)
+}
+
+func (c *UrlRetentionCron) processBatch(ctx context.Context, cutoff time.Time) (int64, error) {
+	if c.retentionConfig.Mode == model.RetentionModeArchive {
+		return c.urlRepository.ArchiveExpiredBatch(ctx, cutoff, c.retentionConfig.BatchSize)
+	}
+	return c.urlRepository.DeleteExpiredBatch(ctx, cutoff, c.retentionConfig.BatchSize)
+}
+
+// finishRun records the outcome of the run. It uses a fresh context so that
+// a run that timed out can still be marked as failed.
+func (c *UrlRetentionCron) finishRun(run *model.RetentionRun, runErr error) {
+	ctx, cancel := context.WithTimeout(context.Background(), constants.DefaultContextTimeout)
+	defer cancel()
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.Status = model.RetentionRunStatusCompleted
+	if runErr != nil {
+		run.Status = model.RetentionRunStatusFailed
+		run.Error = runErr.Error()
+		logger.Log.Errorw("URL Retention: retention run failed", "run_id", run.ID, "mode", run.Mode, "processed", run.ProcessedCount, "error", runErr)
+	} else {
+		logger.Log.Infow("URL Retention: retention run completed", "run_id", run.ID, "mode", run.Mode, "batches", run.BatchCount, "processed", run.ProcessedCount, "duration", finishedAt.Sub(run.StartedAt))
+	}
+
+	if err := c.retentionRunRepository.Update(ctx, run); err != nil {
+		logger.Log.Errorw("URL Retention: failed to update retention run", "run_id", run.ID, "error", err)
+	}
+}
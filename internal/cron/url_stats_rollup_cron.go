@@ -0,0 +1,66 @@
+package cron
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/constants"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/repository"
+	"github.com/Alfian57/belajar-golang/internal/service"
+	"github.com/go-co-op/gocron/v2"
+)
+
+// UrlStatsRollupCron rolls the visits of the days that ended since its last run up into the daily stats.
+type UrlStatsRollupCron struct {
+	scheduler          gocron.Scheduler
+	statsRollupService *service.StatsRollupService
+}
+
+func NewUrlStatsRollupCron(scheduler gocron.Scheduler) *UrlStatsRollupCron {
+	return &UrlStatsRollupCron{
+		scheduler:          scheduler,
+		statsRollupService: service.NewStatsRollupService(repository.NewStatsRollupRepository()),
+	}
+}
+
+func (c *UrlStatsRollupCron) Start(ctx context.Context) error {
+	_, err := c.scheduler.NewJob(
+		gocron.DailyJob(
+			1, // Every 1 day
+			gocron.NewAtTimes(
+				gocron.NewAtTime(0, 30, 0), // At half past midnight, once the visits of the last day are flushed
+			),
+		),
+		gocron.NewTask(
+			func() {
+				// Every run gets its own deadline instead of sharing the startup context
+				runCtx, cancel := context.WithTimeout(ctx, constants.RollupRunTimeout)
+				defer cancel()
+
+				c.run(runCtx)
+			},
+		),
+		gocron.WithSingletonMode(gocron.LimitModeReschedule),
+	)
+
+	if err != nil {
+		return fmt.Errorf("failed to create url stats rollup cron job: %w", err)
+	}
+
+	return nil
+}
+
+func (c *UrlStatsRollupCron) run(ctx context.Context) {
+	startedAt := time.Now()
+
+	// Days that were rolled up before a failure are kept and the next run continues after them
+	result, err := c.statsRollupService.Rollup(ctx)
+	if err != nil {
+		logger.Log.Errorw("URL Stats Rollup: rollup failed", "days", result.Days, "visits", result.Visits, "error", err)
+		return
+	}
+
+	logger.Log.Infow("URL Stats Rollup: rollup completed", "days", result.Days, "visits", result.Visits, "duration", time.Since(startedAt))
+}
@@ -33,7 +33,8 @@ func Init(config config.DatabaseConfig) {
 	// Open a new database connection
 	var err error
 	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: newLogger,
+		Logger:         newLogger,
+		TranslateError: true,
 	})
 	if err != nil {
 		logger.Log.Fatalf("error opening database connection: %v", err)
@@ -11,31 +11,76 @@ import (
 )
 
 func InitializeAuthHandler() *handler.AuthHandler {
-	wire.Build(handler.NewAuthHandler, service.NewAuthService, repository.NewUserRepository, repository.NewRefreshTokenRepository)
+	wire.Build(handler.NewAuthHandler, service.NewAuthService, repository.NewUserRepository, repository.NewRefreshTokenRepository, repository.NewSessionRepository)
 	return &handler.AuthHandler{}
 }
 
 func InitializeUserHandler() *handler.UserHandler {
-	wire.Build(handler.NewUserHandler, service.NewUserService, repository.NewUserRepository)
+	wire.Build(handler.NewUserHandler, service.NewUserService, repository.NewUserRepository, repository.NewSessionRepository)
 	return &handler.UserHandler{}
 }
 
 func InitializeUrlHandler() *handler.UrlHandler {
-	wire.Build(handler.NewUrlHandler, service.NewUrlService, repository.NewUrlRepository, repository.NewUserRepository)
+	wire.Build(handler.NewUrlHandler, service.NewUrlService, service.NewDomainPolicyService, repository.NewUrlRepository, repository.NewUserRepository, repository.NewBannedDomainRepository)
 	return &handler.UrlHandler{}
 }
 
 func InitializeUrlVisitorHandler() *handler.UrlVisitorHandler {
-	wire.Build(handler.NewUrlVisitorHandler, service.NewUrlVisitorService, repository.NewUrlVisitorRepository, repository.NewUrlRepository)
+	wire.Build(handler.NewUrlVisitorHandler, service.NewUrlVisitorService, repository.NewUrlVisitorRepository, repository.NewUrlRepository, repository.NewUniqueVisitorRepository, repository.NewStatsRollupRepository)
 	return &handler.UrlVisitorHandler{}
 }
 
+func InitializeRedirectHandler() *handler.RedirectHandler {
+	wire.Build(handler.NewRedirectHandler, service.NewUrlService, service.NewDomainPolicyService, service.NewUrlVisitorService, repository.NewUrlRepository, repository.NewUserRepository, repository.NewBannedDomainRepository, repository.NewUrlVisitorRepository, repository.NewUniqueVisitorRepository, repository.NewStatsRollupRepository, service.NewBotDetectionService, repository.NewBotPatternRepository)
+	return &handler.RedirectHandler{}
+}
+
 func InitializeBannedDomainHandler() *handler.BannedDomainHandler {
-	wire.Build(handler.NewBannedDomainHandler, service.NewBannedDomainService, repository.NewBannedDomainRepository)
+	wire.Build(handler.NewBannedDomainHandler, service.NewBannedDomainService, service.NewBannedDomainScanService, repository.NewBannedDomainRepository, repository.NewBannedDomainScanRepository, repository.NewUrlRepository)
 	return &handler.BannedDomainHandler{}
 }
 
+func InitializeBannedDomainScanHandler() *handler.BannedDomainScanHandler {
+	wire.Build(handler.NewBannedDomainScanHandler, service.NewBannedDomainScanService, repository.NewBannedDomainScanRepository, repository.NewBannedDomainRepository, repository.NewUrlRepository)
+	return &handler.BannedDomainScanHandler{}
+}
+
+func InitializeBotPatternHandler() *handler.BotPatternHandler {
+	wire.Build(handler.NewBotPatternHandler, service.NewBotPatternService, repository.NewBotPatternRepository)
+	return &handler.BotPatternHandler{}
+}
+
+func InitializeApiKeyHandler() *handler.ApiKeyHandler {
+	wire.Build(handler.NewApiKeyHandler, service.NewApiKeyService, repository.NewApiKeyRepository, repository.NewUserRepository)
+	return &handler.ApiKeyHandler{}
+}
+
+func InitializeSessionHandler() *handler.SessionHandler {
+	wire.Build(handler.NewSessionHandler, service.NewSessionService, repository.NewSessionRepository, repository.NewUserRepository)
+	return &handler.SessionHandler{}
+}
+
 func InitializeUserService() *service.UserService {
-	wire.Build(service.NewUserService, repository.NewUserRepository)
+	wire.Build(service.NewUserService, repository.NewUserRepository, repository.NewSessionRepository)
 	return &service.UserService{}
 }
+
+func InitializeBannedDomainService() *service.BannedDomainService {
+	wire.Build(service.NewBannedDomainService, service.NewBannedDomainScanService, repository.NewBannedDomainRepository, repository.NewBannedDomainScanRepository, repository.NewUrlRepository)
+	return &service.BannedDomainService{}
+}
+
+func InitializeStatsRollupService() *service.StatsRollupService {
+	wire.Build(service.NewStatsRollupService, repository.NewStatsRollupRepository)
+	return &service.StatsRollupService{}
+}
+
+func InitializeApiKeyService() *service.ApiKeyService {
+	wire.Build(service.NewApiKeyService, repository.NewApiKeyRepository, repository.NewUserRepository)
+	return &service.ApiKeyService{}
+}
+
+func InitializeSessionService() *service.SessionService {
+	wire.Build(service.NewSessionService, repository.NewSessionRepository, repository.NewUserRepository)
+	return &service.SessionService{}
+}
@@ -17,14 +17,16 @@ import (
 func InitializeAuthHandler() *handler.AuthHandler {
 	userRepository := repository.NewUserRepository()
 	refreshTokenRepository := repository.NewRefreshTokenRepository()
-	authService := service.NewAuthService(userRepository, refreshTokenRepository)
+	sessionRepository := repository.NewSessionRepository()
+	authService := service.NewAuthService(userRepository, refreshTokenRepository, sessionRepository)
 	authHandler := handler.NewAuthHandler(authService)
 	return authHandler
 }
 
 func InitializeUserHandler() *handler.UserHandler {
 	userRepository := repository.NewUserRepository()
-	userService := service.NewUserService(userRepository)
+	sessionRepository := repository.NewSessionRepository()
+	userService := service.NewUserService(userRepository, sessionRepository)
 	userHandler := handler.NewUserHandler(userService)
 	return userHandler
 }
@@ -32,7 +34,9 @@ func InitializeUserHandler() *handler.UserHandler {
 func InitializeUrlHandler() *handler.UrlHandler {
 	urlRepository := repository.NewUrlRepository()
 	userRepository := repository.NewUserRepository()
-	urlService := service.NewUrlService(urlRepository, userRepository)
+	bannedDomainRepository := repository.NewBannedDomainRepository()
+	domainPolicyService := service.NewDomainPolicyService(bannedDomainRepository)
+	urlService := service.NewUrlService(urlRepository, userRepository, domainPolicyService)
 	urlHandler := handler.NewUrlHandler(urlService)
 	return urlHandler
 }
@@ -40,20 +44,103 @@ func InitializeUrlHandler() *handler.UrlHandler {
 func InitializeUrlVisitorHandler() *handler.UrlVisitorHandler {
 	urlVisitorRepository := repository.NewUrlVisitorRepository()
 	urlRepository := repository.NewUrlRepository()
-	urlVisitorService := service.NewUrlVisitorService(urlVisitorRepository, urlRepository)
+	uniqueVisitorRepository := repository.NewUniqueVisitorRepository()
+	statsRollupRepository := repository.NewStatsRollupRepository()
+	urlVisitorService := service.NewUrlVisitorService(urlVisitorRepository, urlRepository, uniqueVisitorRepository, statsRollupRepository)
 	urlVisitorHandler := handler.NewUrlVisitorHandler(urlVisitorService)
 	return urlVisitorHandler
 }
 
+func InitializeRedirectHandler() *handler.RedirectHandler {
+	urlRepository := repository.NewUrlRepository()
+	userRepository := repository.NewUserRepository()
+	bannedDomainRepository := repository.NewBannedDomainRepository()
+	domainPolicyService := service.NewDomainPolicyService(bannedDomainRepository)
+	urlService := service.NewUrlService(urlRepository, userRepository, domainPolicyService)
+	urlVisitorRepository := repository.NewUrlVisitorRepository()
+	uniqueVisitorRepository := repository.NewUniqueVisitorRepository()
+	statsRollupRepository := repository.NewStatsRollupRepository()
+	urlVisitorService := service.NewUrlVisitorService(urlVisitorRepository, urlRepository, uniqueVisitorRepository, statsRollupRepository)
+	botPatternRepository := repository.NewBotPatternRepository()
+	botDetectionService := service.NewBotDetectionService(botPatternRepository)
+	redirectHandler := handler.NewRedirectHandler(urlService, urlVisitorService, botDetectionService)
+	return redirectHandler
+}
+
 func InitializeBannedDomainHandler() *handler.BannedDomainHandler {
 	bannedDomainRepository := repository.NewBannedDomainRepository()
-	bannedDomainService := service.NewBannedDomainService(bannedDomainRepository)
+	bannedDomainScanRepository := repository.NewBannedDomainScanRepository()
+	urlRepository := repository.NewUrlRepository()
+	bannedDomainScanService := service.NewBannedDomainScanService(bannedDomainScanRepository, bannedDomainRepository, urlRepository)
+	bannedDomainService := service.NewBannedDomainService(bannedDomainRepository, bannedDomainScanService)
 	bannedDomainHandler := handler.NewBannedDomainHandler(bannedDomainService)
 	return bannedDomainHandler
 }
 
+func InitializeBannedDomainScanHandler() *handler.BannedDomainScanHandler {
+	bannedDomainScanRepository := repository.NewBannedDomainScanRepository()
+	bannedDomainRepository := repository.NewBannedDomainRepository()
+	urlRepository := repository.NewUrlRepository()
+	bannedDomainScanService := service.NewBannedDomainScanService(bannedDomainScanRepository, bannedDomainRepository, urlRepository)
+	bannedDomainScanHandler := handler.NewBannedDomainScanHandler(bannedDomainScanService)
+	return bannedDomainScanHandler
+}
+
+func InitializeBotPatternHandler() *handler.BotPatternHandler {
+	botPatternRepository := repository.NewBotPatternRepository()
+	botPatternService := service.NewBotPatternService(botPatternRepository)
+	botPatternHandler := handler.NewBotPatternHandler(botPatternService)
+	return botPatternHandler
+}
+
+func InitializeApiKeyHandler() *handler.ApiKeyHandler {
+	apiKeyRepository := repository.NewApiKeyRepository()
+	userRepository := repository.NewUserRepository()
+	apiKeyService := service.NewApiKeyService(apiKeyRepository, userRepository)
+	apiKeyHandler := handler.NewApiKeyHandler(apiKeyService)
+	return apiKeyHandler
+}
+
+func InitializeSessionHandler() *handler.SessionHandler {
+	sessionRepository := repository.NewSessionRepository()
+	userRepository := repository.NewUserRepository()
+	sessionService := service.NewSessionService(sessionRepository, userRepository)
+	sessionHandler := handler.NewSessionHandler(sessionService)
+	return sessionHandler
+}
+
 func InitializeUserService() *service.UserService {
 	userRepository := repository.NewUserRepository()
-	userService := service.NewUserService(userRepository)
+	sessionRepository := repository.NewSessionRepository()
+	userService := service.NewUserService(userRepository, sessionRepository)
 	return userService
 }
+
+func InitializeBannedDomainService() *service.BannedDomainService {
+	bannedDomainRepository := repository.NewBannedDomainRepository()
+	bannedDomainScanRepository := repository.NewBannedDomainScanRepository()
+	urlRepository := repository.NewUrlRepository()
+	bannedDomainScanService := service.NewBannedDomainScanService(bannedDomainScanRepository, bannedDomainRepository, urlRepository)
+	bannedDomainService := service.NewBannedDomainService(bannedDomainRepository, bannedDomainScanService)
+	return bannedDomainService
+}
+
+func InitializeStatsRollupService() *service.StatsRollupService {
+	statsRollupRepository := repository.NewStatsRollupRepository()
+	statsRollupService := service.NewStatsRollupService(statsRollupRepository)
+	return statsRollupService
+}
+
+func InitializeApiKeyService() *service.ApiKeyService {
+	apiKeyRepository := repository.NewApiKeyRepository()
+	userRepository := repository.NewUserRepository()
+	apiKeyService := service.NewApiKeyService(apiKeyRepository, userRepository)
+	return apiKeyService
+}
+
+func InitializeSessionService() *service.SessionService {
+	sessionRepository := repository.NewSessionRepository()
+	userRepository := repository.NewUserRepository()
+	sessionService := service.NewSessionService(sessionRepository, userRepository)
+	return sessionService
+}
@@ -0,0 +1,19 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/model"
+)
+
+type CreateApiKeyRequest struct {
+	Name      string     `json:"name" form:"name" binding:"required,min=1,max=100"`
+	Scopes    []string   `json:"scopes" form:"scopes" binding:"omitempty,dive,oneof=urls:read urls:write stats:read api_keys sessions admin"`
+	ExpiresAt *time.Time `json:"expires_at" form:"expires_at"`
+}
+
+// CreatedApiKey holds a new key, which is only ever returned here.
+type CreatedApiKey struct {
+	ApiKey model.ApiKey `json:"api_key"`
+	Key    string       `json:"key"`
+}
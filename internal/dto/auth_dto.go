@@ -1,8 +1,9 @@
 package dto
 
 type LoginRequest struct {
-	Username string `json:"username" form:"username" binding:"required"`
-	Password string `json:"password" form:"password" binding:"required"`
+	Username   string `json:"username" form:"username" binding:"required"`
+	Password   string `json:"password" form:"password" binding:"required"`
+	DeviceName string `json:"device_name" form:"device_name" binding:"omitempty,max=100"`
 }
 
 type RegisterRequest struct {
@@ -12,6 +13,16 @@ type RegisterRequest struct {
 	PasswordConfirmation string `json:"password_confirmation" form:"password_confirmation" binding:"required,eqfield=Password"`
 }
 
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" form:"refresh_token" binding:"required"`
+}
+
+// SessionClient describes the client that logs in or refreshes a session.
+type SessionClient struct {
+	UserAgent string
+	IpAddress string
+}
+
 type Credentials struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
@@ -3,17 +3,34 @@ package dto
 import "github.com/google/uuid"
 
 type CreateBannedDomainRequest struct {
-	Url string `json:"url" form:"url" binding:"required,min=3,max=255,url"`
+	Url      string `json:"url" form:"url" binding:"required,min=3,max=255"`
+	RuleType string `json:"rule_type" form:"rule_type" binding:"omitempty,oneof=exact wildcard path_prefix regex"`
 }
 
 type UpdateBannedDomainRequest struct {
-	ID  uuid.UUID `json:"id" form:"id"`
-	Url string    `json:"url" form:"url" binding:"required,min=3,max=255,url"`
+	ID       uuid.UUID `json:"id" form:"id"`
+	Url      string    `json:"url" form:"url" binding:"required,min=3,max=255"`
+	RuleType string    `json:"rule_type" form:"rule_type" binding:"omitempty,oneof=exact wildcard path_prefix regex"`
 }
 
 type GetBannedDomainsFilter struct {
 	PaginationRequest
 	Search    string `json:"search" form:"search" binding:"omitempty,max=255"`
-	OrderBy   string `json:"order_by" form:"order_by" binding:"omitempty,oneof=url created_at"`
+	OrderBy   string `json:"order_by" form:"order_by" binding:"omitempty,oneof=url rule_type created_at"`
 	OrderType string `json:"order_type" form:"order_type" binding:"omitempty,oneof=ASC DESC asc desc"`
 }
+
+type ImportBannedDomainsRequest struct {
+	Format string `json:"format" form:"format" binding:"omitempty,oneof=auto hosts plain csv"`
+}
+
+type ImportBannedDomainsResult struct {
+	Added   int `json:"added"`
+	Skipped int `json:"skipped"`
+	Invalid int `json:"invalid"`
+}
+
+type GetBannedDomainScansFilter struct {
+	PaginationRequest
+	Status string `json:"status" form:"status" binding:"omitempty,oneof=running completed failed"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "github.com/google/uuid"
+
+type CreateBotPatternRequest struct {
+	Pattern     string `json:"pattern" form:"pattern" binding:"required,min=2,max=255"`
+	Description string `json:"description" form:"description" binding:"omitempty,max=255"`
+}
+
+type UpdateBotPatternRequest struct {
+	ID          uuid.UUID `json:"id" form:"id"`
+	Pattern     string    `json:"pattern" form:"pattern" binding:"required,min=2,max=255"`
+	Description string    `json:"description" form:"description" binding:"omitempty,max=255"`
+}
+
+type GetBotPatternsFilter struct {
+	PaginationRequest
+	Search    string `json:"search" form:"search" binding:"omitempty,max=255"`
+	OrderBy   string `json:"order_by" form:"order_by" binding:"omitempty,oneof=pattern created_at"`
+	OrderType string `json:"order_type" form:"order_type" binding:"omitempty,oneof=ASC DESC asc desc"`
+}
@@ -7,21 +7,42 @@ import (
 )
 
 type CreateUrlRequest struct {
-	ShortUrl  string    `json:"short_url" form:"short_url" binding:"required,min=3,max=255"`
-	LongUrl   string    `json:"long_url" form:"long_url" binding:"required,min=3,max=255,url"`
-	UserID    string    `json:"user_id" form:"user_id" binding:"required,uuid"`
-	ExpiredAt time.Time `json:"expired_at" form:"expired_at" binding:"required"`
+	ShortUrl   string     `json:"short_url" form:"short_url" binding:"omitempty,min=3,max=100"`
+	LongUrl    string     `json:"long_url" form:"long_url" binding:"required,min=3,max=255,url"`
+	UserID     string     `json:"user_id" form:"user_id" binding:"required,uuid"`
+	ExpiredAt  time.Time  `json:"expired_at" form:"expired_at" binding:"required"`
+	ActiveFrom *time.Time `json:"active_from" form:"active_from"`
+	MaxClicks  *int       `json:"max_clicks" form:"max_clicks" binding:"omitempty,min=1"`
+	Password   string     `json:"password" form:"password" binding:"omitempty,min=4,max=72"`
+}
+
+type CreateMyUrlRequest struct {
+	ShortUrl   string     `json:"short_url" form:"short_url" binding:"omitempty,min=3,max=100"`
+	LongUrl    string     `json:"long_url" form:"long_url" binding:"required,min=3,max=255,url"`
+	ExpiredAt  time.Time  `json:"expired_at" form:"expired_at" binding:"required"`
+	ActiveFrom *time.Time `json:"active_from" form:"active_from"`
+	MaxClicks  *int       `json:"max_clicks" form:"max_clicks" binding:"omitempty,min=1"`
+	Password   string     `json:"password" form:"password" binding:"omitempty,min=4,max=72"`
 }
 
 type UpdateUrlRequest struct {
-	ID        uuid.UUID `json:"id" form:"id"`
-	ShortUrl  string    `json:"short_url" form:"short_url" binding:"required,min=3,max=255"`
-	LongUrl   string    `json:"long_url" form:"long_url" binding:"required,min=3,max=255,url"`
-	ExpiredAt time.Time `json:"expired_at" form:"expired_at" binding:"required"`
+	ID             uuid.UUID  `json:"id" form:"id"`
+	ShortUrl       string     `json:"short_url" form:"short_url" binding:"required,min=3,max=100"`
+	LongUrl        string     `json:"long_url" form:"long_url" binding:"required,min=3,max=255,url"`
+	ExpiredAt      time.Time  `json:"expired_at" form:"expired_at" binding:"required"`
+	ActiveFrom     *time.Time `json:"active_from" form:"active_from"`
+	MaxClicks      *int       `json:"max_clicks" form:"max_clicks" binding:"omitempty,min=1"`
+	Password       string     `json:"password" form:"password" binding:"omitempty,min=4,max=72"`
+	RemovePassword bool       `json:"remove_password" form:"remove_password"`
+}
+
+type UnlockUrlRequest struct {
+	Password string `json:"password" form:"password" binding:"required,max=72"`
 }
 
 type GetUrlsFilter struct {
 	PaginationRequest
+	UserID    string `json:"user_id" form:"user_id" binding:"omitempty,uuid"`
 	Search    string `json:"search" form:"search" binding:"omitempty,max=255"`
 	OrderBy   string `json:"order_by" form:"order_by" binding:"omitempty,oneof=short_url long_url created_at"`
 	OrderType string `json:"order_type" form:"order_type" binding:"omitempty,oneof=ASC DESC asc desc"`
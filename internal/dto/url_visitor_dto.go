@@ -0,0 +1,83 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RecordVisitRequest struct {
+	UrlID     uuid.UUID
+	IpAddress string
+	UserAgent string
+	Referrer  string
+	Method    string
+	Accept    string
+	IsBot     bool
+}
+
+type VisitorCount struct {
+	Total  int64     `json:"total"`
+	Unique uint64    `json:"unique"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+}
+
+type CountVisitorsFilter struct {
+	StatsRangeFilter
+}
+
+type StatsRangeFilter struct {
+	From        *time.Time `json:"from" form:"from"`
+	To          *time.Time `json:"to" form:"to"`
+	Timezone    string     `json:"timezone" form:"timezone" binding:"omitempty,max=64"`
+	IncludeBots bool       `json:"include_bots" form:"include_bots"`
+}
+
+type GetTimeseriesFilter struct {
+	StatsRangeFilter
+	Interval string `json:"interval" form:"interval" binding:"omitempty,oneof=hour day week"`
+}
+
+type TimeseriesPoint struct {
+	Bucket time.Time `json:"bucket"`
+	Count  int64     `json:"count"`
+}
+
+type TimeseriesResult struct {
+	Interval string            `json:"interval"`
+	Timezone string            `json:"timezone"`
+	From     time.Time         `json:"from"`
+	To       time.Time         `json:"to"`
+	Unique   uint64            `json:"unique"`
+	Points   []TimeseriesPoint `json:"points"`
+}
+
+type GetVisitorBreakdownFilter struct {
+	StatsRangeFilter
+	Dimension string `json:"dimension" form:"dimension" binding:"required,oneof=referrer device os os_version browser browser_version country region city"`
+	Limit     int    `json:"limit" form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type VisitorBreakdownItem struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type VisitorBreakdownResult struct {
+	Dimension string                 `json:"dimension"`
+	From      time.Time              `json:"from"`
+	To        time.Time              `json:"to"`
+	Unique    uint64                 `json:"unique"`
+	Items     []VisitorBreakdownItem `json:"items"`
+}
+
+type ExportVisitorsFilter struct {
+	StatsRangeFilter
+	Format string `json:"format" form:"format" binding:"omitempty,oneof=csv ndjson"`
+}
+
+type StatsRollupResult struct {
+	Days   int   `json:"days"`
+	Visits int64 `json:"visits"`
+}
@@ -71,10 +71,27 @@ var (
 
 	ErrUserNotFound  = &AppError{Code: http.StatusNotFound, Message: "user not found"}
 	ErrUsernameExist = &AppError{Code: http.StatusUnprocessableEntity, Message: "username already exists"}
+	ErrUserBanned    = &AppError{Code: http.StatusForbidden, Message: "user is banned"}
 
-	ErrUrlNotFound = &AppError{Code: http.StatusNotFound, Message: "url not found"}
+	ErrUrlNotFound   = &AppError{Code: http.StatusNotFound, Message: "url not found"}
+	ErrUrlExpired    = &AppError{Code: http.StatusGone, Message: "url has expired"}
+	ErrShortUrlExist = &AppError{Code: http.StatusUnprocessableEntity, Message: "short url already exists"}
+	ErrUrlBlocked    = &AppError{Code: http.StatusForbidden, Message: "url is blocked"}
+	ErrUrlNotActive  = &AppError{Code: http.StatusForbidden, Message: "url is not active yet"}
+	ErrUrlClickLimit = &AppError{Code: http.StatusGone, Message: "url has reached its click limit"}
 
-	ErrBannedDomainNotFound = &AppError{Code: http.StatusNotFound, Message: "banned domain not found"}
+	ErrUrlPasswordIncorrect = &AppError{Code: http.StatusUnauthorized, Message: "url password is incorrect"}
+	ErrTooManyAttempts      = &AppError{Code: http.StatusTooManyRequests, Message: "too many attempts, try again later"}
+
+	ErrBannedDomainNotFound     = &AppError{Code: http.StatusNotFound, Message: "banned domain not found"}
+	ErrBannedDomainScanNotFound = &AppError{Code: http.StatusNotFound, Message: "banned domain scan not found"}
+
+	ErrBotPatternNotFound = &AppError{Code: http.StatusNotFound, Message: "bot pattern not found"}
+	ErrBotPatternExist    = &AppError{Code: http.StatusUnprocessableEntity, Message: "bot pattern already exists"}
+
+	ErrApiKeyNotFound = &AppError{Code: http.StatusNotFound, Message: "api key not found"}
+
+	ErrSessionNotFound = &AppError{Code: http.StatusNotFound, Message: "session not found"}
 
 	ErrInternalServer = &AppError{Code: http.StatusInternalServerError, Message: "internal server error"}
 	ErrBadRequest     = &AppError{Code: http.StatusBadRequest, Message: "bad request"}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/Alfian57/belajar-golang/internal/utils/shortcode"
 	"github.com/bluele/factory-go/factory"
 	"github.com/brianvoe/gofakeit/v6"
 	"github.com/google/uuid"
@@ -19,7 +20,7 @@ func NewUrlFactory(userIDs uuid.UUIDs) *factory.Factory {
 	).SeqInt("ID", func(n int) (any, error) {
 		return uuid.New(), nil
 	}).Attr("ShortUrl", func(args factory.Args) (any, error) {
-		return gofakeit.Word(), nil
+		return shortcode.Generate(7)
 	}).Attr("LongUrl", func(args factory.Args) (any, error) {
 		return gofakeit.URL(), nil
 	}).Attr("UserID", func(args factory.Args) (any, error) {
@@ -0,0 +1,175 @@
+package geoip
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/config"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// Location is the part of a GeoIP2 / GeoLite2 City record that is stored for visitors.
+type Location struct {
+	Country string
+	Region  string
+	City    string
+}
+
+type record struct {
+	Country struct {
+		IsoCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	Subdivisions []struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"subdivisions"`
+	City struct {
+		Names map[string]string `maxminddb:"names"`
+	} `maxminddb:"city"`
+}
+
+type database struct {
+	reader  *maxminddb.Reader
+	modTime time.Time
+	size    int64
+}
+
+// current holds the open database, or nil when GeoIP lookups are disabled.
+// The database is read into memory, so a replaced reader can simply be dropped
+// without breaking lookups that still use it.
+var current atomic.Pointer[database]
+
+// Init opens the configured database and watches the file for replacements.
+// Lookups stay disabled when no database path is configured, and until the file can be opened
+// when it is missing or invalid at startup.
+func Init(cfg config.GeoIPConfig) {
+	if cfg.DatabasePath == "" {
+		logger.Log.Infoln("GeoIP: no database configured, lookups are disabled")
+		return
+	}
+
+	db, err := open(cfg.DatabasePath)
+	if err != nil {
+		logger.Log.Errorw("GeoIP: failed to open database, lookups are disabled until it can be loaded", "path", cfg.DatabasePath, "error", err)
+	} else {
+		current.Store(db)
+		logger.Log.Infow("GeoIP: database loaded", "path", cfg.DatabasePath, "type", db.reader.Metadata.DatabaseType)
+	}
+
+	// The watcher also loads a database that wasn't available at startup
+	if cfg.ReloadInterval > 0 {
+		go watch(context.Background(), cfg.DatabasePath, cfg.ReloadInterval)
+	}
+}
+
+// Enabled reports whether a database is loaded.
+func Enabled() bool {
+	return current.Load() != nil
+}
+
+// Lookup returns the location of ip. It returns false when lookups are disabled,
+// ip is invalid or the database has no record for it.
+func Lookup(ip string) (Location, bool) {
+	db := current.Load()
+	if db == nil {
+		return Location{}, false
+	}
+
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return Location{}, false
+	}
+
+	var result record
+	if err := db.reader.Lookup(parsed, &result); err != nil {
+		logger.Log.Warnw("GeoIP: lookup failed", "error", err)
+		return Location{}, false
+	}
+
+	location := Location{
+		Country: result.Country.IsoCode,
+		City:    result.City.Names["en"],
+	}
+	if len(result.Subdivisions) > 0 {
+		location.Region = result.Subdivisions[0].Names["en"]
+	}
+
+	if location == (Location{}) {
+		return Location{}, false
+	}
+	return location, true
+}
+
+func open(path string) (*database, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := maxminddb.FromBytes(buffer)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database: %w", err)
+	}
+
+	return &database{reader: reader, modTime: info.ModTime(), size: info.Size()}, nil
+}
+
+// watch loads the database once the file on disk appears and reloads it whenever it is replaced.
+// A file that can't be read keeps the previous database, if any, in use.
+func watch(ctx context.Context, path string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// A file that failed to open is only retried once it changes
+	var failedModTime time.Time
+	var failedSize int64
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		previous := current.Load()
+
+		info, err := os.Stat(path)
+		if err != nil {
+			// Don't repeat the startup error until the file shows up
+			if previous != nil {
+				logger.Log.Warnw("GeoIP: failed to stat database", "path", path, "error", err)
+			}
+			continue
+		}
+
+		if previous != nil && info.ModTime().Equal(previous.modTime) && info.Size() == previous.size {
+			continue
+		}
+		if info.ModTime().Equal(failedModTime) && info.Size() == failedSize {
+			continue
+		}
+
+		db, err := open(path)
+		if err != nil {
+			logger.Log.Errorw("GeoIP: failed to reload database, keeping the previous one", "path", path, "error", err)
+			failedModTime, failedSize = info.ModTime(), info.Size()
+			continue
+		}
+		current.Store(db)
+
+		if previous == nil {
+			logger.Log.Infow("GeoIP: database loaded", "path", path, "type", db.reader.Metadata.DatabaseType)
+			continue
+		}
+		logger.Log.Infow("GeoIP: database reloaded", "path", path, "build_epoch", db.reader.Metadata.BuildEpoch)
+	}
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/Alfian57/belajar-golang/internal/response"
+	"github.com/Alfian57/belajar-golang/internal/service"
+	"github.com/Alfian57/belajar-golang/internal/utils/auth"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ApiKeyHandler struct {
+	apiKeyService *service.ApiKeyService
+}
+
+func NewApiKeyHandler(service *service.ApiKeyService) *ApiKeyHandler {
+	return &ApiKeyHandler{
+		apiKeyService: service,
+	}
+}
+
+func (h *ApiKeyHandler) GetMyApiKeys(ctx *gin.Context) {
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	apiKeys, err := h.apiKeyService.GetUserApiKeys(ctx, user.ID)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, apiKeys)
+}
+
+func (h *ApiKeyHandler) CreateMyApiKey(ctx *gin.Context) {
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	var request dto.CreateApiKeyRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	var currentKey *model.ApiKey
+	if apiKey, ok := auth.GetCurrentApiKey(ctx); ok {
+		currentKey = &apiKey
+	}
+
+	createdApiKey, err := h.apiKeyService.CreateUserApiKey(ctx, user, currentKey, request)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusCreated, createdApiKey)
+}
+
+func (h *ApiKeyHandler) DeleteMyApiKey(ctx *gin.Context) {
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	if err := h.apiKeyService.DeleteUserApiKey(ctx, user.ID, id); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteMessageResponse(ctx, http.StatusOK, "api key successfully revoked")
+}
@@ -26,7 +26,7 @@ func (h *AuthHandler) Login(ctx *gin.Context) {
 		return
 	}
 
-	credentials, err := h.service.Login(ctx, request)
+	credentials, err := h.service.Login(ctx, request, sessionClient(ctx))
 	if err != nil {
 		response.WriteErrorResponse(ctx, err)
 		return
@@ -38,7 +38,8 @@ func (h *AuthHandler) Login(ctx *gin.Context) {
 	ctx.SetCookie("access_token", credentials.AccessToken, accessTokenMaxAge, "/", "", false, true)
 	ctx.SetCookie("refresh_token", credentials.RefreshToken, refreshTokenMaxAge, "/", "", false, true)
 
-	response.WriteMessageResponse(ctx, http.StatusOK, "user successfully logged in")
+	// Clients without a cookie jar keep the tokens from the body
+	response.WriteDataResponse(ctx, http.StatusOK, credentials)
 }
 
 func (h *AuthHandler) Register(ctx *gin.Context) {
@@ -63,7 +64,7 @@ func (h *AuthHandler) Refresh(ctx *gin.Context) {
 		return
 	}
 
-	credentials, err := h.service.Refresh(ctx, refreshToken)
+	credentials, err := h.service.Refresh(ctx, refreshToken, sessionClient(ctx))
 	if err != nil {
 		response.WriteErrorResponse(ctx, err)
 		return
@@ -78,17 +79,52 @@ func (h *AuthHandler) Refresh(ctx *gin.Context) {
 	response.WriteMessageResponse(ctx, http.StatusOK, "token successfully refreshed")
 }
 
-func (h *AuthHandler) Logout(ctx *gin.Context) {
-	refreshToken, err := ctx.Cookie("refresh_token")
+// RefreshToken refreshes the tokens of clients that don't use cookies.
+// The refresh token is read from the body and the new tokens are returned in the body.
+func (h *AuthHandler) RefreshToken(ctx *gin.Context) {
+	var request dto.RefreshTokenRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	credentials, err := h.service.Refresh(ctx, request.RefreshToken, sessionClient(ctx))
 	if err != nil {
 		response.WriteErrorResponse(ctx, err)
 		return
 	}
 
+	response.WriteDataResponse(ctx, http.StatusOK, credentials)
+}
+
+func (h *AuthHandler) Logout(ctx *gin.Context) {
+	// Clients that don't use cookies send their refresh token in the body
+	refreshToken, err := ctx.Cookie("refresh_token")
+	if err != nil {
+		var request dto.RefreshTokenRequest
+		if err := ctx.ShouldBind(&request); err != nil {
+			response.WriteErrorResponse(ctx, err)
+			return
+		}
+		refreshToken = request.RefreshToken
+	}
+
 	h.service.Logout(ctx, refreshToken)
 
-	ctx.SetCookie("access_token", "", -1, "/", "", false, true)
-	ctx.SetCookie("refresh_token", "", -1, "/", "", false, true)
+	clearAuthCookies(ctx)
 
 	response.WriteMessageResponse(ctx, http.StatusOK, "user successfully logged out")
 }
+
+// sessionClient describes the client of the request for its session.
+func sessionClient(ctx *gin.Context) dto.SessionClient {
+	return dto.SessionClient{
+		UserAgent: ctx.Request.UserAgent(),
+		IpAddress: ctx.ClientIP(),
+	}
+}
+
+func clearAuthCookies(ctx *gin.Context) {
+	ctx.SetCookie("access_token", "", -1, "/", "", false, true)
+	ctx.SetCookie("refresh_token", "", -1, "/", "", false, true)
+}
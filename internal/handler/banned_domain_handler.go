@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/Alfian57/belajar-golang/internal/dto"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
 	"github.com/Alfian57/belajar-golang/internal/response"
 	"github.com/Alfian57/belajar-golang/internal/service"
 	"github.com/gin-gonic/gin"
@@ -53,6 +54,36 @@ func (h *BannedDomainHandler) CreateBannedDomain(ctx *gin.Context) {
 	response.WriteMessageResponse(ctx, http.StatusCreated, "banned domain successfully created")
 }
 
+func (h *BannedDomainHandler) ImportBannedDomains(ctx *gin.Context) {
+	var request dto.ImportBannedDomainsRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		fieldError := errs.NewFieldError("file", "file is required")
+		response.WriteErrorResponse(ctx, errs.NewValidationError([]errs.FieldError{fieldError}))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.WriteErrorResponse(ctx, errs.NewAppError(http.StatusBadRequest, "failed to open file", err))
+		return
+	}
+	defer file.Close()
+
+	result, err := h.bannedDomainService.ImportBannedDomains(ctx, file, request.Format)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, result)
+}
+
 func (h *BannedDomainHandler) UpdateBannedDomain(ctx *gin.Context) {
 	var request dto.UpdateBannedDomainRequest
 	if err := ctx.ShouldBind(&request); err != nil {
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	"github.com/Alfian57/belajar-golang/internal/response"
+	"github.com/Alfian57/belajar-golang/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type BannedDomainScanHandler struct {
+	service *service.BannedDomainScanService
+}
+
+func NewBannedDomainScanHandler(s *service.BannedDomainScanService) *BannedDomainScanHandler {
+	return &BannedDomainScanHandler{
+		service: s,
+	}
+}
+
+func (h *BannedDomainScanHandler) GetAllScans(ctx *gin.Context) {
+	var query dto.GetBannedDomainScansFilter
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	query.PaginationRequest.SetDefaults()
+
+	result, err := h.service.GetAllScans(ctx, query)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WritePaginatedResponse(ctx, http.StatusOK, result)
+}
+
+func (h *BannedDomainScanHandler) GetScanByID(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	scan, err := h.service.GetScanByID(ctx, id.String())
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, scan)
+}
+
+func (h *BannedDomainScanHandler) ScanBannedDomain(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	scan, err := h.service.ScanBannedDomain(ctx, id)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusAccepted, scan)
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	"github.com/Alfian57/belajar-golang/internal/response"
+	"github.com/Alfian57/belajar-golang/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type BotPatternHandler struct {
+	botPatternService *service.BotPatternService
+}
+
+func NewBotPatternHandler(service *service.BotPatternService) *BotPatternHandler {
+	return &BotPatternHandler{
+		botPatternService: service,
+	}
+}
+
+func (h *BotPatternHandler) GetAllBotPatterns(ctx *gin.Context) {
+	var query dto.GetBotPatternsFilter
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	query.PaginationRequest.SetDefaults()
+
+	result, err := h.botPatternService.GetAllBotPatterns(ctx, query)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WritePaginatedResponse(ctx, http.StatusOK, result)
+}
+
+func (h *BotPatternHandler) CreateBotPattern(ctx *gin.Context) {
+	var request dto.CreateBotPatternRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	if err := h.botPatternService.CreateBotPattern(ctx, request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteMessageResponse(ctx, http.StatusCreated, "bot pattern successfully created")
+}
+
+func (h *BotPatternHandler) UpdateBotPattern(ctx *gin.Context) {
+	var request dto.UpdateBotPatternRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+	request.ID = id
+
+	if err := h.botPatternService.UpdateBotPattern(ctx, request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteMessageResponse(ctx, http.StatusOK, "bot pattern successfully updated")
+}
+
+func (h *BotPatternHandler) DeleteBotPattern(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	if err := h.botPatternService.DeleteBotPattern(ctx, id); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteMessageResponse(ctx, http.StatusOK, "bot pattern successfully deleted")
+}
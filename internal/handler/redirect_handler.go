@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/Alfian57/belajar-golang/internal/response"
+	"github.com/Alfian57/belajar-golang/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+)
+
+const urlAccessCookiePrefix = "url_access_"
+
+var passwordPromptTemplate = template.Must(template.New("password_prompt").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="utf-8">
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<meta name="robots" content="noindex">
+	<title>Password required</title>
+</head>
+<body>
+	<form method="POST" action="/{{.Code}}">
+		<h1>This link is password protected</h1>
+		{{if .Error}}<p role="alert">{{.Error}}</p>{{end}}
+		<label for="password">Password</label>
+		<input id="password" name="password" type="password" autocomplete="off" required autofocus>
+		<button type="submit">Continue</button>
+	</form>
+</body>
+</html>
+`))
+
+type RedirectHandler struct {
+	urlService          *service.UrlService
+	urlVisitorService   *service.UrlVisitorService
+	botDetectionService *service.BotDetectionService
+}
+
+func NewRedirectHandler(urlService *service.UrlService, urlVisitorService *service.UrlVisitorService, botDetectionService *service.BotDetectionService) *RedirectHandler {
+	return &RedirectHandler{
+		urlService:          urlService,
+		urlVisitorService:   urlVisitorService,
+		botDetectionService: botDetectionService,
+	}
+}
+
+func (h *RedirectHandler) Redirect(ctx *gin.Context) {
+	url, err := h.urlService.ResolveShortUrl(ctx, ctx.Param("code"))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	// Ask for the password unless the visitor already unlocked the url
+	token, _ := ctx.Cookie(urlAccessCookiePrefix + url.ShortUrl)
+	if !h.urlService.HasUrlAccess(url, token) {
+		writePasswordPrompt(ctx, http.StatusOK, url.ShortUrl, "")
+		return
+	}
+
+	h.redirect(ctx, url, http.StatusFound)
+}
+
+func (h *RedirectHandler) Unlock(ctx *gin.Context) {
+	code := ctx.Param("code")
+
+	var request dto.UnlockUrlRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		writePasswordPrompt(ctx, http.StatusUnprocessableEntity, code, "password is required")
+		return
+	}
+
+	url, err := h.urlService.UnlockUrl(ctx, code, request.Password, ctx.ClientIP())
+	if err != nil {
+		switch {
+		case errors.Is(err, errs.ErrUrlPasswordIncorrect):
+			writePasswordPrompt(ctx, http.StatusUnauthorized, code, "incorrect password")
+		case errors.Is(err, errs.ErrTooManyAttempts):
+			writePasswordPrompt(ctx, http.StatusTooManyRequests, code, "too many attempts, try again later")
+		default:
+			response.WriteErrorResponse(ctx, err)
+		}
+		return
+	}
+
+	// Remember the unlocked url so the visitor isn't asked again
+	if url.HasPassword {
+		token, ttl := h.urlService.CreateUrlAccessToken(url)
+		ctx.SetSameSite(http.SameSiteLaxMode)
+		ctx.SetCookie(urlAccessCookiePrefix+url.ShortUrl, token, int(ttl.Seconds()), "/"+url.ShortUrl, "", ctx.Request.TLS != nil, true)
+	}
+
+	h.redirect(ctx, url, http.StatusSeeOther)
+}
+
+func (h *RedirectHandler) redirect(ctx *gin.Context, url model.Url, statusCode int) {
+	visit := dto.RecordVisitRequest{
+		UrlID:     url.ID,
+		IpAddress: ctx.ClientIP(),
+		UserAgent: ctx.Request.UserAgent(),
+		Referrer:  ctx.Request.Referer(),
+		Method:    ctx.Request.Method,
+		Accept:    ctx.GetHeader("Accept"),
+	}
+	// Bots are only tagged for the stats, every redirect uses up a click of a limited url
+	// since the bot checks rely on headers that any client can send
+	visit.IsBot = h.botDetectionService.IsBot(ctx, visit)
+
+	if err := h.urlService.RegisterClick(ctx, url); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	// A failed visit record must not break the redirect, the service already logs it
+	_ = h.urlVisitorService.RecordVisit(ctx, visit)
+
+	ctx.Redirect(statusCode, url.LongUrl)
+}
+
+func writePasswordPrompt(ctx *gin.Context, statusCode int, code string, message string) {
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Render(statusCode, render.HTML{
+		Template: passwordPromptTemplate,
+		Data: gin.H{
+			"Code":  code,
+			"Error": message,
+		},
+	})
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"net/http"
+
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/response"
+	"github.com/Alfian57/belajar-golang/internal/service"
+	"github.com/Alfian57/belajar-golang/internal/utils/auth"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SessionHandler struct {
+	sessionService *service.SessionService
+}
+
+func NewSessionHandler(service *service.SessionService) *SessionHandler {
+	return &SessionHandler{
+		sessionService: service,
+	}
+}
+
+func (h *SessionHandler) GetMySessions(ctx *gin.Context) {
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	sessions, err := h.sessionService.GetUserSessions(ctx, user.ID, auth.GetCurrentSessionID(ctx))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, sessions)
+}
+
+func (h *SessionHandler) DeleteMySession(ctx *gin.Context) {
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	if err := h.sessionService.RevokeUserSession(ctx, user.ID, id); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	// Revoking the current session logs the browser out as well
+	if id.String() == auth.GetCurrentSessionID(ctx) {
+		clearAuthCookies(ctx)
+	}
+
+	response.WriteMessageResponse(ctx, http.StatusOK, "session successfully revoked")
+}
+
+// DeleteMySessions logs the current user out everywhere, including the current session.
+func (h *SessionHandler) DeleteMySessions(ctx *gin.Context) {
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	if err := h.sessionService.RevokeUserSessions(ctx, user.ID); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	clearAuthCookies(ctx)
+
+	response.WriteMessageResponse(ctx, http.StatusOK, "all sessions successfully revoked")
+}
+
+func (h *SessionHandler) GetUserSessions(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	sessions, err := h.sessionService.GetUserSessions(ctx, id, auth.GetCurrentSessionID(ctx))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, sessions)
+}
+
+func (h *SessionHandler) DeleteUserSessions(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	if err := h.sessionService.RevokeUserSessions(ctx, id); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteMessageResponse(ctx, http.StatusOK, "all sessions of the user successfully revoked")
+}
@@ -4,9 +4,11 @@ import (
 	"net/http"
 
 	"github.com/Alfian57/belajar-golang/internal/dto"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
 	"github.com/Alfian57/belajar-golang/internal/logger"
 	"github.com/Alfian57/belajar-golang/internal/response"
 	"github.com/Alfian57/belajar-golang/internal/service"
+	"github.com/Alfian57/belajar-golang/internal/utils/auth"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -112,6 +114,23 @@ func (h *UrlHandler) DeleteUrl(ctx *gin.Context) {
 	response.WriteMessageResponse(ctx, http.StatusOK, "url successfully deleted")
 }
 
+func (h *UrlHandler) UnblockUrl(ctx *gin.Context) {
+	idParam := ctx.Param("id")
+
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	if err := h.service.UnblockUrl(ctx, id); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteMessageResponse(ctx, http.StatusOK, "url successfully unblocked")
+}
+
 func (h *UrlHandler) CountAllUrl(ctx *gin.Context) {
 	count, err := h.service.Count(ctx)
 	if err != nil {
@@ -121,3 +140,128 @@ func (h *UrlHandler) CountAllUrl(ctx *gin.Context) {
 
 	response.WriteDataResponse(ctx, http.StatusOK, count)
 }
+
+func (h *UrlHandler) GetMyUrls(ctx *gin.Context) {
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	var query dto.GetUrlsFilter
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	query.PaginationRequest.SetDefaults()
+	query.UserID = user.ID.String()
+
+	result, err := h.service.GetAllUrls(ctx, query)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WritePaginatedResponse(ctx, http.StatusOK, result)
+}
+
+func (h *UrlHandler) CreateMyUrl(ctx *gin.Context) {
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	var request dto.CreateMyUrlRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	if err := h.service.CreateUrl(ctx, dto.CreateUrlRequest{
+		ShortUrl:   request.ShortUrl,
+		LongUrl:    request.LongUrl,
+		UserID:     user.ID.String(),
+		ActiveFrom: request.ActiveFrom,
+		ExpiredAt:  request.ExpiredAt,
+		MaxClicks:  request.MaxClicks,
+		Password:   request.Password,
+	}); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteMessageResponse(ctx, http.StatusCreated, "url successfully created")
+}
+
+func (h *UrlHandler) GetMyUrlByID(ctx *gin.Context) {
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	url, err := h.service.GetUserUrlByID(ctx, user.ID, id.String())
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, url)
+}
+
+func (h *UrlHandler) UpdateMyUrl(ctx *gin.Context) {
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	var request dto.UpdateUrlRequest
+	if err := ctx.ShouldBind(&request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+	request.ID = id
+
+	if err := h.service.UpdateUserUrl(ctx, user.ID, request); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteMessageResponse(ctx, http.StatusOK, "url successfully updated")
+}
+
+func (h *UrlHandler) DeleteMyUrl(ctx *gin.Context) {
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	if err := h.service.DeleteUserUrl(ctx, user.ID, id); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteMessageResponse(ctx, http.StatusOK, "url successfully deleted")
+}
@@ -1,11 +1,16 @@
 package handler
 
 import (
+	"io"
 	"net/http"
 
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
 	"github.com/Alfian57/belajar-golang/internal/response"
 	"github.com/Alfian57/belajar-golang/internal/service"
+	"github.com/Alfian57/belajar-golang/internal/utils/auth"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type UrlVisitorHandler struct {
@@ -19,7 +24,13 @@ func NewUrlVisitorHandler(s *service.UrlVisitorService) *UrlVisitorHandler {
 }
 
 func (h *UrlVisitorHandler) CountAllUrlVisitors(ctx *gin.Context) {
-	count, err := h.service.Count(ctx)
+	var query dto.CountVisitorsFilter
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	count, err := h.service.Count(ctx, query)
 	if err != nil {
 		response.WriteErrorResponse(ctx, err)
 		return
@@ -31,7 +42,13 @@ func (h *UrlVisitorHandler) CountAllUrlVisitors(ctx *gin.Context) {
 func (h *UrlVisitorHandler) CountUrlVisitorByID(ctx *gin.Context) {
 	urlIDParam := ctx.Param("urlID")
 
-	count, err := h.service.CountByUrlID(ctx, urlIDParam)
+	var query dto.CountVisitorsFilter
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	count, err := h.service.CountByUrlID(ctx, urlIDParam, query)
 	if err != nil {
 		response.WriteErrorResponse(ctx, err)
 		return
@@ -39,3 +56,305 @@ func (h *UrlVisitorHandler) CountUrlVisitorByID(ctx *gin.Context) {
 
 	response.WriteDataResponse(ctx, http.StatusOK, count)
 }
+
+func (h *UrlVisitorHandler) CountMyVisitors(ctx *gin.Context) {
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	var query dto.CountVisitorsFilter
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	count, err := h.service.CountUser(ctx, user.ID, query)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, count)
+}
+
+func (h *UrlVisitorHandler) CountMyUrlVisitors(ctx *gin.Context) {
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	var query dto.CountVisitorsFilter
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	count, err := h.service.CountUserByUrlID(ctx, user.ID, id.String(), query)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, count)
+}
+
+func (h *UrlVisitorHandler) GetUrlTimeseries(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	var query dto.GetTimeseriesFilter
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	result, err := h.service.GetTimeseriesByUrlID(ctx, id.String(), query)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, result)
+}
+
+func (h *UrlVisitorHandler) GetMyUrlTimeseries(ctx *gin.Context) {
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	var query dto.GetTimeseriesFilter
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	result, err := h.service.GetUserTimeseriesByUrlID(ctx, user.ID, id.String(), query)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, result)
+}
+
+func (h *UrlVisitorHandler) GetUrlBreakdown(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	var query dto.GetVisitorBreakdownFilter
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	result, err := h.service.GetBreakdownByUrlID(ctx, id.String(), query)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, result)
+}
+
+func (h *UrlVisitorHandler) GetUserBreakdown(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	var query dto.GetVisitorBreakdownFilter
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	result, err := h.service.GetUserBreakdown(ctx, id, query)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, result)
+}
+
+func (h *UrlVisitorHandler) GetMyUrlBreakdown(ctx *gin.Context) {
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	var query dto.GetVisitorBreakdownFilter
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	result, err := h.service.GetUserBreakdownByUrlID(ctx, user.ID, id.String(), query)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, result)
+}
+
+func (h *UrlVisitorHandler) GetMyBreakdown(ctx *gin.Context) {
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	var query dto.GetVisitorBreakdownFilter
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	result, err := h.service.GetUserBreakdown(ctx, user.ID, query)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	response.WriteDataResponse(ctx, http.StatusOK, result)
+}
+
+func (h *UrlVisitorHandler) ExportUrlVisitors(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	var query dto.ExportVisitorsFilter
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	export, err := h.service.ExportByUrlID(ctx, id.String(), query)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	writeVisitorExport(ctx, export)
+}
+
+func (h *UrlVisitorHandler) ExportUserVisitors(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	var query dto.ExportVisitorsFilter
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	export, err := h.service.ExportUser(ctx, id, query)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	writeVisitorExport(ctx, export)
+}
+
+func (h *UrlVisitorHandler) ExportMyUrlVisitors(ctx *gin.Context) {
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	var query dto.ExportVisitorsFilter
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	export, err := h.service.ExportUserByUrlID(ctx, user.ID, id.String(), query)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	writeVisitorExport(ctx, export)
+}
+
+func (h *UrlVisitorHandler) ExportMyVisitors(ctx *gin.Context) {
+	user, ok := auth.GetCurrentUser(ctx)
+	if !ok {
+		response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		return
+	}
+
+	var query dto.ExportVisitorsFilter
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	export, err := h.service.ExportUser(ctx, user.ID, query)
+	if err != nil {
+		response.WriteErrorResponse(ctx, err)
+		return
+	}
+
+	writeVisitorExport(ctx, export)
+}
+
+// writeVisitorExport streams an export as a download. Once the first rows are sent the status
+// can no longer change, so a failure halfway through ends the download early; the service logs it.
+func writeVisitorExport(ctx *gin.Context, export service.VisitorExport) {
+	ctx.Header("Content-Type", export.ContentType)
+	ctx.Header("Content-Disposition", `attachment; filename="`+export.Filename+`"`)
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Status(http.StatusOK)
+
+	ctx.Stream(func(w io.Writer) bool {
+		_ = export.Write(ctx.Request.Context(), w)
+		return false
+	})
+}
@@ -0,0 +1,288 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/config"
+	"github.com/Alfian57/belajar-golang/internal/constants"
+	"github.com/Alfian57/belajar-golang/internal/geoip"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/Alfian57/belajar-golang/internal/repository"
+	"github.com/Alfian57/belajar-golang/internal/utils/hll"
+	"github.com/Alfian57/belajar-golang/internal/utils/ipanon"
+	"github.com/Alfian57/belajar-golang/internal/utils/text"
+	"github.com/google/uuid"
+)
+
+// Policies applied by Enqueue when the buffer is full.
+const (
+	PolicyBlock = "block"
+	PolicyDrop  = "drop"
+)
+
+// maxLocationLength matches the size of the url_visitors.region and city columns.
+const maxLocationLength = 100
+
+var (
+	// ErrQueueFull is returned by Enqueue when the buffer is full and the visit was dropped.
+	ErrQueueFull = errors.New("visit queue is full")
+	// ErrClosed is returned by Enqueue when the pipeline isn't running.
+	ErrClosed = errors.New("visit pipeline is closed")
+)
+
+type pipeline struct {
+	cfg                     config.IngestConfig
+	visitorCfg              config.VisitorConfig
+	queue                   chan model.URLVisitor
+	urlVisitorRepository    *repository.UrlVisitorRepository
+	uniqueVisitorRepository *repository.UniqueVisitorRepository
+
+	// mu guards closed so that no visit is sent on the queue once it is closed
+	mu      sync.RWMutex
+	closed  bool
+	wg      sync.WaitGroup
+	dropped atomic.Int64
+}
+
+// current holds the running pipeline, or nil when it isn't started.
+var current atomic.Pointer[pipeline]
+
+// Init starts the workers that store the enqueued visits in batches.
+// Visitor addresses are anonymised by the workers according to visitorCfg.
+// It must be called after the database and GeoIP are initialised.
+func Init(cfg config.IngestConfig, visitorCfg config.VisitorConfig) {
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
+	}
+	if cfg.FlushSize < 1 {
+		cfg.FlushSize = 1
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = time.Second
+	}
+	if cfg.Policy != PolicyBlock && cfg.Policy != PolicyDrop {
+		logger.Log.Warnw("Ingest: invalid policy, falling back to drop", "policy", cfg.Policy)
+		cfg.Policy = PolicyDrop
+	}
+
+	// Never fall back to storing full addresses on a misconfigured mode
+	if !ipanon.IsValidMode(visitorCfg.IpMode) {
+		logger.Log.Warnw("Ingest: invalid visitor ip mode, falling back to truncate", "mode", visitorCfg.IpMode)
+		visitorCfg.IpMode = ipanon.ModeTruncate
+	}
+
+	p := &pipeline{
+		cfg:                     cfg,
+		visitorCfg:              visitorCfg,
+		queue:                   make(chan model.URLVisitor, cfg.BufferSize),
+		urlVisitorRepository:    repository.NewUrlVisitorRepository(),
+		uniqueVisitorRepository: repository.NewUniqueVisitorRepository(),
+	}
+
+	for i := 0; i < cfg.Workers; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+	current.Store(p)
+
+	logger.Log.Infow("Ingest: visit pipeline started", "workers", cfg.Workers, "buffer_size", cfg.BufferSize, "flush_size", cfg.FlushSize, "flush_interval", cfg.FlushInterval, "policy", cfg.Policy)
+}
+
+// Enqueue hands a visit over to the workers, its CreatedAt should be set to the time of the visit
+// and its ClientIP to the full address of the visitor.
+// When the buffer is full the visit is dropped with the drop policy, while the block policy
+// waits for room until ctx is done.
+func Enqueue(ctx context.Context, visitor model.URLVisitor) error {
+	p := current.Load()
+	if p == nil {
+		return ErrClosed
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if p.closed {
+		return ErrClosed
+	}
+
+	if p.cfg.Policy == PolicyBlock {
+		select {
+		case p.queue <- visitor:
+			return nil
+		case <-ctx.Done():
+			p.dropped.Add(1)
+			return ctx.Err()
+		}
+	}
+
+	select {
+	case p.queue <- visitor:
+		return nil
+	default:
+		p.dropped.Add(1)
+		return ErrQueueFull
+	}
+}
+
+// Shutdown stops accepting visits and waits until the buffered visits are stored.
+// It returns ctx's error when the buffer isn't drained before ctx is done.
+func Shutdown(ctx context.Context) error {
+	p := current.Swap(nil)
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	p.closed = true
+	close(p.queue)
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		logger.Log.Infoln("Ingest: visit pipeline drained")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// work collects visits from the queue and flushes them once the batch is full,
+// the flush interval elapsed or the queue is closed.
+func (p *pipeline) work() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]model.URLVisitor, 0, p.cfg.FlushSize)
+	for {
+		select {
+		case visitor, ok := <-p.queue:
+			if !ok {
+				p.flush(batch)
+				return
+			}
+			batch = append(batch, visitor)
+			if len(batch) >= p.cfg.FlushSize {
+				p.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			p.flush(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+// flush enriches and stores a batch of visits and adds them to the unique visitor sketches.
+// A failed batch is logged and dropped so that a database outage doesn't fill up the memory.
+func (p *pipeline) flush(visitors []model.URLVisitor) {
+	if dropped := p.dropped.Swap(0); dropped > 0 {
+		logger.Log.Warnw("Ingest: visits dropped because the queue was full", "count", dropped)
+	}
+	if len(visitors) == 0 {
+		return
+	}
+
+	for i := range visitors {
+		p.enrich(&visitors[i])
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), constants.IngestFlushTimeout)
+	defer cancel()
+
+	if err := p.urlVisitorRepository.CreateBatch(ctx, visitors); err != nil {
+		logger.Log.Errorw("Ingest: failed to store visits", "count", len(visitors), "error", err)
+		return
+	}
+
+	p.countUniqueVisits(ctx, visitors)
+}
+
+// enrich looks up the location of the visitor and then anonymises its address, so that the
+// lookup is kept off the redirect and the full address is never stored.
+func (p *pipeline) enrich(visitor *model.URLVisitor) {
+	if location, ok := geoip.Lookup(visitor.ClientIP); ok {
+		visitor.Country = location.Country
+		visitor.Region = text.Truncate(location.Region, maxLocationLength)
+		visitor.City = text.Truncate(location.City, maxLocationLength)
+	}
+
+	ipAddress, ipHash := ipanon.Anonymize(visitor.ClientIP, p.visitorCfg.IpMode, p.visitorCfg.IpHashSecret)
+	visitor.IpAddress = text.NullableString(ipAddress)
+	visitor.IpHash = text.NullableString(ipHash)
+	visitor.ClientIP = ""
+}
+
+// countUniqueVisits adds the fingerprints of the visitors to the sketches of their url and
+// of all urls for the day of the visit. Bot visits are kept in sketches of their own.
+// The visits are grouped first so that each sketch row is only locked once per batch.
+// A failed merge only skews the unique counts.
+func (p *pipeline) countUniqueVisits(ctx context.Context, visitors []model.URLVisitor) {
+	type urlDay struct {
+		urlID uuid.UUID
+		day   time.Time
+		isBot bool
+	}
+	type day struct {
+		day   time.Time
+		isBot bool
+	}
+
+	urlSketches := make(map[urlDay]*hll.Sketch)
+	daySketches := make(map[day]*hll.Sketch)
+	for _, visitor := range visitors {
+		fingerprint := visitorFingerprint(visitor)
+		visitDay := utcDay(visitor.CreatedAt)
+
+		urlKey := urlDay{urlID: visitor.UrlID, day: visitDay, isBot: visitor.IsBot}
+		if urlSketches[urlKey] == nil {
+			urlSketches[urlKey] = hll.New()
+		}
+		urlSketches[urlKey].AddString(fingerprint)
+
+		dayKey := day{day: visitDay, isBot: visitor.IsBot}
+		if daySketches[dayKey] == nil {
+			daySketches[dayKey] = hll.New()
+		}
+		daySketches[dayKey].AddString(fingerprint)
+	}
+
+	for key, sketch := range urlSketches {
+		if err := p.uniqueVisitorRepository.MergeUrlSketch(ctx, key.urlID, key.day, key.isBot, sketch); err != nil {
+			logger.Log.Errorw("Ingest: failed to update url unique visitor sketch", "url_id", key.urlID, "error", err)
+		}
+	}
+	for key, sketch := range daySketches {
+		if err := p.uniqueVisitorRepository.MergeSketch(ctx, key.day, key.isBot, sketch); err != nil {
+			logger.Log.Errorw("Ingest: failed to update unique visitor sketch", "error", err)
+		}
+	}
+}
+
+// visitorFingerprint identifies a visitor by its anonymised ip address, or its hash, and user agent.
+func visitorFingerprint(visitor model.URLVisitor) string {
+	ip := ""
+	if visitor.IpAddress != nil {
+		ip = *visitor.IpAddress
+	} else if visitor.IpHash != nil {
+		ip = *visitor.IpHash
+	}
+	return ip + "|" + visitor.UserAgent
+}
+
+func utcDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
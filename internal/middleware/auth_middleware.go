@@ -1,9 +1,12 @@
 package middleware
 
 import (
+	"strings"
+
 	"github.com/Alfian57/belajar-golang/internal/di"
 	errs "github.com/Alfian57/belajar-golang/internal/errors"
 	"github.com/Alfian57/belajar-golang/internal/response"
+	"github.com/Alfian57/belajar-golang/internal/utils/apikey"
 	"github.com/Alfian57/belajar-golang/internal/utils/jwt"
 	"github.com/gin-gonic/gin"
 )
@@ -12,36 +15,99 @@ func AuthMiddleware() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authService := di.InitializeUserService()
 
-		accessToken, err := ctx.Cookie("access_token")
+		// Scripts authenticate with an API key, mobile and CLI clients send the access token
+		// as a bearer token and browsers send it in a cookie
+		token, hasBearer := bearerToken(ctx)
+		if hasBearer && apikey.IsKey(token) {
+			apiKeyService := di.InitializeApiKeyService()
+
+			user, apiKey, err := apiKeyService.Authenticate(ctx, token)
+			if err != nil {
+				response.WriteErrorResponse(ctx, err)
+				ctx.Abort()
+				return
+			}
+
+			if user.IsBanned {
+				response.WriteErrorResponse(ctx, errs.ErrUserBanned)
+				ctx.Abort()
+				return
+			}
+
+			ctx.Set("api_key", apiKey)
+			ctx.Set("user", user)
+
+			ctx.Next()
+			return
+		}
+
+		accessToken := token
+		if !hasBearer {
+			cookie, err := ctx.Cookie("access_token")
+			if err != nil {
+				response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+				ctx.Abort()
+				return
+			}
+			accessToken = cookie
+		}
+
+		claims, err := jwt.ValidateAccessToken(accessToken)
 		if err != nil {
 			response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
 			ctx.Abort()
 			return
 		}
 
-		userID, err := jwt.ValidateAccessToken(accessToken)
-		if err != nil {
+		if claims.UserID == "" {
 			response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
 			ctx.Abort()
 			return
 		}
 
-		if userID == "" {
-			response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+		// Access tokens of revoked sessions are rejected before they expire
+		sessionService := di.InitializeSessionService()
+		if err := sessionService.ValidateSession(ctx, claims.UserID, claims.SessionID); err != nil {
+			response.WriteErrorResponse(ctx, err)
 			ctx.Abort()
 			return
 		}
 
-		user, err := authService.GetUserByID(ctx, userID)
+		user, err := authService.GetUserByID(ctx, claims.UserID)
 		if err != nil {
 			response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
 			ctx.Abort()
 			return
 		}
 
+		if user.IsBanned {
+			response.WriteErrorResponse(ctx, errs.ErrUserBanned)
+			ctx.Abort()
+			return
+		}
+
+		// Access tokens issued before the token version was incremented are no longer valid
+		if claims.TokenVersion != user.TokenVersion {
+			response.WriteErrorResponse(ctx, errs.ErrUnauthorized)
+			ctx.Abort()
+			return
+		}
+
 		ctx.Set("access_token", accessToken)
+		ctx.Set("session_id", claims.SessionID)
 		ctx.Set("user", user)
 
 		ctx.Next()
 	}
 }
+
+// bearerToken returns the token of an "Authorization: Bearer <token>" header.
+func bearerToken(ctx *gin.Context) (string, bool) {
+	scheme, token, ok := strings.Cut(ctx.GetHeader("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
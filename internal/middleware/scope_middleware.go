@@ -0,0 +1,23 @@
+package middleware
+
+import (
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/response"
+	"github.com/Alfian57/belajar-golang/internal/utils/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// ScopeMiddleware rejects requests authenticated with an API key that isn't granted scope.
+// Requests authenticated with an access token and keys without scopes always pass.
+func ScopeMiddleware(scope string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		apiKey, ok := auth.GetCurrentApiKey(ctx)
+		if ok && !apiKey.HasScope(scope) {
+			response.WriteErrorResponse(ctx, errs.ErrForbidden)
+			ctx.Abort()
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	ApiKeyScopeUrlsRead  = "urls:read"
+	ApiKeyScopeUrlsWrite = "urls:write"
+	ApiKeyScopeStatsRead = "stats:read"
+	ApiKeyScopeApiKeys   = "api_keys"
+	ApiKeyScopeSessions  = "sessions"
+	ApiKeyScopeAdmin     = "admin"
+)
+
+// ApiKeyDefaultScopes are granted to keys created without scopes. They leave out api_keys and admin,
+// so that a key has to be granted those explicitly.
+var ApiKeyDefaultScopes = []string{ApiKeyScopeSessions, ApiKeyScopeStatsRead, ApiKeyScopeUrlsRead, ApiKeyScopeUrlsWrite}
+
+// ApiKey authenticates scripts as its user. Only the SHA-256 hash of the key is stored,
+// Prefix keeps the start of the key so that the user can tell their keys apart.
+// Keys stored without scopes, from before scopes had defaults, get ApiKeyDefaultScopes.
+type ApiKey struct {
+	ID         uuid.UUID  `json:"id" gorm:"primaryKey"`
+	UserID     uuid.UUID  `json:"user_id" gorm:"not null"`
+	Name       string     `json:"name" gorm:"not null"`
+	Prefix     string     `json:"prefix" gorm:"not null"`
+	KeyHash    string     `json:"-" gorm:"uniqueIndex;not null"`
+	Scopes     string     `json:"-" gorm:"not null;default:''"`
+	ScopeList  []string   `json:"scopes" gorm:"-"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (ApiKey) TableName() string {
+	return "api_keys"
+}
+
+// AfterFind exposes the stored scopes as a list.
+func (k *ApiKey) AfterFind(tx *gorm.DB) error {
+	k.ScopeList = strings.Fields(k.Scopes)
+	if len(k.ScopeList) == 0 {
+		k.ScopeList = slices.Clone(ApiKeyDefaultScopes)
+	}
+	return nil
+}
+
+func (k *ApiKey) SetScopes(scopes []string) {
+	if scopes == nil {
+		scopes = []string{}
+	}
+	k.ScopeList = scopes
+	k.Scopes = strings.Join(scopes, " ")
+}
+
+// HasScope reports whether the key may be used for scope.
+func (k *ApiKey) HasScope(scope string) bool {
+	if len(k.ScopeList) == 0 {
+		return slices.Contains(ApiKeyDefaultScopes, scope)
+	}
+	return slices.Contains(k.ScopeList, scope)
+}
+
+// IsExpired reports whether the key can no longer be used.
+func (k *ApiKey) IsExpired() bool {
+	return k.ExpiresAt != nil && !time.Now().Before(*k.ExpiresAt)
+}
@@ -6,9 +6,17 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	BannedDomainRuleExact      = "exact"
+	BannedDomainRuleWildcard   = "wildcard"
+	BannedDomainRulePathPrefix = "path_prefix"
+	BannedDomainRuleRegex      = "regex"
+)
+
 type BannedDomain struct {
 	ID        uuid.UUID `gorm:"primaryKey"`
 	URL       string    `gorm:"not null"`
+	RuleType  string    `json:"rule_type" gorm:"not null;default:exact"`
 	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
 }
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	BannedDomainScanStatusRunning   = "running"
+	BannedDomainScanStatusCompleted = "completed"
+	BannedDomainScanStatusFailed    = "failed"
+)
+
+type BannedDomainScan struct {
+	ID             uuid.UUID  `json:"id" gorm:"primaryKey"`
+	BannedDomainID *uuid.UUID `json:"banned_domain_id"`
+	RuleCount      int        `json:"rule_count" gorm:"not null"`
+	Status         string     `json:"status" gorm:"not null"`
+	ScannedCount   int        `json:"scanned_count" gorm:"not null"`
+	MatchedCount   int        `json:"matched_count" gorm:"not null"`
+	BlockedCount   int        `json:"blocked_count" gorm:"not null"`
+	UnblockedCount int        `json:"unblocked_count" gorm:"not null"`
+	Error          string     `json:"error,omitempty" gorm:"not null"`
+	StartedAt      time.Time  `json:"started_at" gorm:"not null"`
+	FinishedAt     *time.Time `json:"finished_at"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (BannedDomainScan) TableName() string {
+	return "banned_domain_scans"
+}
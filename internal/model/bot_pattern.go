@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BotPattern flags visits whose User-Agent contains Pattern, compared case-insensitively, as bot visits.
+type BotPattern struct {
+	ID          uuid.UUID `json:"id" gorm:"primaryKey"`
+	Pattern     string    `json:"pattern" gorm:"not null"`
+	Description string    `json:"description" gorm:"not null"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (BotPattern) TableName() string {
+	return "bot_patterns"
+}
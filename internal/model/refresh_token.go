@@ -6,14 +6,24 @@ import (
 	"github.com/google/uuid"
 )
 
+// RefreshToken stores the SHA-256 hash of an issued refresh token. Every token issued by
+// refreshing another one joins the family of the login that started the chain, which is its Session.
+// RotatedAt is set once the token was exchanged for a new one, so that reusing it can be detected.
 type RefreshToken struct {
-	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
-	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null"`
-	TokenHash string    `json:"token_hash" gorm:"not null"`
-	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
-	ExpiresAt int64     `json:"expires_at" gorm:"not null"`
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;not null"`
+	FamilyID  uuid.UUID  `json:"family_id" gorm:"type:uuid;not null"`
+	TokenHash string     `json:"-" gorm:"uniqueIndex;not null"`
+	RotatedAt *time.Time `json:"rotated_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	ExpiresAt int64      `json:"expires_at" gorm:"not null"`
 }
 
 func (RefreshToken) TableName() string {
 	return "refresh_tokens"
 }
+
+// IsExpired reports whether the token can no longer be exchanged.
+func (t *RefreshToken) IsExpired() bool {
+	return time.Now().Unix() >= t.ExpiresAt
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	RetentionModeDelete  = "delete"
+	RetentionModeArchive = "archive"
+)
+
+const (
+	RetentionRunStatusRunning   = "running"
+	RetentionRunStatusCompleted = "completed"
+	RetentionRunStatusFailed    = "failed"
+)
+
+type RetentionRun struct {
+	ID             uuid.UUID  `json:"id" gorm:"primaryKey"`
+	Mode           string     `json:"mode" gorm:"not null"`
+	Status         string     `json:"status" gorm:"not null"`
+	Cutoff         time.Time  `json:"cutoff" gorm:"not null"`
+	BatchCount     int        `json:"batch_count" gorm:"not null"`
+	ProcessedCount int        `json:"processed_count" gorm:"not null"`
+	Error          string     `json:"error,omitempty" gorm:"not null"`
+	StartedAt      time.Time  `json:"started_at" gorm:"not null"`
+	FinishedAt     *time.Time `json:"finished_at"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (RetentionRun) TableName() string {
+	return "retention_runs"
+}
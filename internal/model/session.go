@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is a login on one device. Its ID is the family ID of the refresh tokens issued
+// to the device and the "sid" claim of its access tokens, so deleting the session revokes both.
+type Session struct {
+	ID         uuid.UUID `json:"id" gorm:"primaryKey"`
+	UserID     uuid.UUID `json:"user_id" gorm:"not null"`
+	Device     string    `json:"device" gorm:"not null"`
+	UserAgent  string    `json:"user_agent" gorm:"not null"`
+	IpAddress  *string   `json:"ip_address" gorm:"type:inet"`
+	LastUsedAt time.Time `json:"last_used_at" gorm:"not null"`
+	ExpiresAt  time.Time `json:"expires_at" gorm:"not null"`
+	IsCurrent  bool      `json:"is_current" gorm:"-"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// IsExpired reports whether the session can no longer be refreshed.
+func (s *Session) IsExpired() bool {
+	return !time.Now().Before(s.ExpiresAt)
+}
@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UrlUniqueVisitorsDaily holds the HyperLogLog sketch of the visitors of a url on a single UTC day.
+// Bot visits are kept in a sketch of their own.
+type UrlUniqueVisitorsDaily struct {
+	UrlID     uuid.UUID `json:"url_id" gorm:"primaryKey"`
+	Day       time.Time `json:"day" gorm:"primaryKey;type:date"`
+	IsBot     bool      `json:"is_bot" gorm:"primaryKey"`
+	Sketch    []byte    `json:"-" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (UrlUniqueVisitorsDaily) TableName() string {
+	return "url_unique_visitors_daily"
+}
+
+// UniqueVisitorsDaily holds the HyperLogLog sketch of the visitors of all urls on a single UTC day.
+// Bot visits are kept in a sketch of their own.
+type UniqueVisitorsDaily struct {
+	Day       time.Time `json:"day" gorm:"primaryKey;type:date"`
+	IsBot     bool      `json:"is_bot" gorm:"primaryKey"`
+	Sketch    []byte    `json:"-" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (UniqueVisitorsDaily) TableName() string {
+	return "unique_visitors_daily"
+}
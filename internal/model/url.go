@@ -3,19 +3,57 @@ package model
 import (
 	"time"
 
+	"github.com/Alfian57/belajar-golang/internal/utils/hash"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Url struct {
-	ID        uuid.UUID `json:"id" gorm:"primaryKey"`
-	ShortUrl  string    `json:"short_url" gorm:"not null"`
-	LongUrl   string    `json:"long_url" gorm:"not null"`
-	UserID    uuid.UUID `json:"user_id" gorm:"not null"`
-	ExpiredAt time.Time `json:"expired_at" gorm:"not null"`
-	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID          uuid.UUID  `json:"id" gorm:"primaryKey"`
+	ShortUrl    string     `json:"short_url" gorm:"uniqueIndex;not null"`
+	LongUrl     string     `json:"long_url" gorm:"not null"`
+	UserID      uuid.UUID  `json:"user_id" gorm:"not null"`
+	ActiveFrom  *time.Time `json:"active_from"`
+	ExpiredAt   time.Time  `json:"expired_at" gorm:"not null"`
+	MaxClicks   *int       `json:"max_clicks"`
+	ClickCount  int        `json:"click_count" gorm:"default:0"`
+	ArchivedAt  *time.Time `json:"archived_at"`
+	IsBlocked   bool       `json:"is_blocked" gorm:"default:false"`
+	BlockedByID *uuid.UUID `json:"blocked_by_id"`
+	Password    string     `json:"-" gorm:"not null;default:''"`
+	HasPassword bool       `json:"has_password" gorm:"-"`
+	CreatedAt   time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
 func (Url) TableName() string {
 	return "urls"
 }
+
+// IsClickLimitReached reports whether the url has been visited as often as it allows.
+func (u *Url) IsClickLimitReached() bool {
+	return u.MaxClicks != nil && u.ClickCount >= *u.MaxClicks
+}
+
+// AfterFind exposes whether the url is password protected without leaking the hash.
+func (u *Url) AfterFind(tx *gorm.DB) error {
+	u.HasPassword = u.Password != ""
+	return nil
+}
+
+func (u *Url) SetHashedPassword(password string) error {
+	hashedPass, err := hash.HashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	u.Password = hashedPass
+	u.HasPassword = true
+
+	return nil
+}
+
+func (u *Url) CheckHashedPassword(password string) error {
+	err := hash.CheckPasswordHash(password, u.Password)
+	return err
+}
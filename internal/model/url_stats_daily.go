@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UrlStatsDimensionTotal is the dimension of the rows that hold the total number of visits
+// of a url on a day. Its value is always empty.
+const UrlStatsDimensionTotal = "total"
+
+// UrlStatsDaily holds the number of visits of a url on a single UTC day that share the
+// same value of a dimension, such as the country or the device type of the visitors.
+// Bot visits are counted separately so that they can be left out of the stats.
+type UrlStatsDaily struct {
+	UrlID     uuid.UUID `json:"url_id" gorm:"primaryKey"`
+	Day       time.Time `json:"day" gorm:"primaryKey;type:date"`
+	Dimension string    `json:"dimension" gorm:"primaryKey"`
+	Value     string    `json:"value" gorm:"primaryKey"`
+	IsBot     bool      `json:"is_bot" gorm:"primaryKey"`
+	Count     int64     `json:"count" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (UrlStatsDaily) TableName() string {
+	return "url_stats_daily"
+}
+
+// UrlStatsRollup records that the visits of a UTC day were rolled up into UrlStatsDaily.
+type UrlStatsRollup struct {
+	Day        time.Time `json:"day" gorm:"primaryKey;type:date"`
+	VisitCount int64     `json:"visit_count" gorm:"not null"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (UrlStatsRollup) TableName() string {
+	return "url_stats_rollups"
+}
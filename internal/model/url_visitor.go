@@ -7,12 +7,27 @@ import (
 )
 
 type URLVisitor struct {
-	ID        uuid.UUID `json:"id" gorm:"primaryKey"`
-	UrlID     uuid.UUID `json:"url_id" gorm:"not null;index"`
-	IpAddress string    `json:"ip_address" gorm:"not null"`
-	UserAgent string    `json:"user_agent" gorm:"not null"`
-	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID             uuid.UUID `json:"id" gorm:"primaryKey"`
+	UrlID          uuid.UUID `json:"url_id" gorm:"not null;index"`
+	IpAddress      *string   `json:"ip_address" gorm:"type:inet"`
+	IpHash         *string   `json:"ip_hash"`
+	UserAgent      string    `json:"user_agent" gorm:"not null"`
+	DeviceType     string    `json:"device_type" gorm:"not null"`
+	OSFamily       string    `json:"os_family" gorm:"not null"`
+	OSVersion      string    `json:"os_version" gorm:"not null"`
+	BrowserFamily  string    `json:"browser_family" gorm:"not null"`
+	BrowserVersion string    `json:"browser_version" gorm:"not null"`
+	IsBot          bool      `json:"is_bot" gorm:"default:false"`
+	ReferrerHost   string    `json:"referrer_host" gorm:"not null"`
+	Country        string    `json:"country" gorm:"not null"`
+	Region         string    `json:"region" gorm:"not null"`
+	City           string    `json:"city" gorm:"not null"`
+	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// ClientIP is the full address of the visitor, which is only kept in memory until
+	// the visit is enriched and anonymised before it is stored.
+	ClientIP string `json:"-" gorm:"-"`
 }
 
 func (URLVisitor) TableName() string {
@@ -12,15 +12,18 @@ const (
 	UserRoleMember = "member"
 )
 
+// User is an account. TokenVersion is stored in the "ver" claim of access tokens;
+// incrementing it invalidates every access token issued before.
 type User struct {
-	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
-	Email     string    `json:"email" gorm:"uniqueIndex;not null"`
-	Username  string    `json:"username" gorm:"uniqueIndex;not null"`
-	Password  string    `json:"-" gorm:"not null"`
-	Role      string    `json:"role" gorm:"not null"`
-	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
-	IsBanned  bool      `json:"is_banned" gorm:"default:false"`
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key"`
+	Email        string    `json:"email" gorm:"uniqueIndex;not null"`
+	Username     string    `json:"username" gorm:"uniqueIndex;not null"`
+	Password     string    `json:"-" gorm:"not null"`
+	Role         string    `json:"role" gorm:"not null"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	IsBanned     bool      `json:"is_banned" gorm:"default:false"`
+	TokenVersion int       `json:"-" gorm:"not null;default:0"`
 }
 
 func (User) TableName() string {
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/database"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ApiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewApiKeyRepository() *ApiKeyRepository {
+	return &ApiKeyRepository{db: database.DB}
+}
+
+// GetAllByUserID retrieves the keys of a user, newest first
+func (r *ApiKeyRepository) GetAllByUserID(ctx context.Context, userID string) ([]model.ApiKey, error) {
+	var apiKeys []model.ApiKey
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&apiKeys).Error
+	return apiKeys, err
+}
+
+func (r *ApiKeyRepository) CountByUserID(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.ApiKey{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *ApiKeyRepository) Create(ctx context.Context, apiKey *model.ApiKey) error {
+	apiKey.ID = uuid.New()
+
+	return r.db.WithContext(ctx).Create(apiKey).Error
+}
+
+func (r *ApiKeyRepository) GetByKeyHash(ctx context.Context, keyHash string) (model.ApiKey, error) {
+	var apiKey model.ApiKey
+
+	err := r.db.WithContext(ctx).First(&apiKey, "key_hash = ?", keyHash).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return apiKey, errs.ErrApiKeyNotFound
+		}
+		return apiKey, err
+	}
+
+	return apiKey, nil
+}
+
+// UpdateLastUsedAt records when a key was last used without touching updated_at
+func (r *ApiKeyRepository) UpdateLastUsedAt(ctx context.Context, id uuid.UUID, lastUsedAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.ApiKey{}).Where("id = ?", id).UpdateColumn("last_used_at", lastUsedAt).Error
+}
+
+func (r *ApiKeyRepository) DeleteByIDAndUserID(ctx context.Context, id string, userID string) error {
+	result := r.db.WithContext(ctx).Delete(&model.ApiKey{}, "id = ? AND user_id = ?", id, userID)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.ErrApiKeyNotFound
+	}
+
+	return nil
+}
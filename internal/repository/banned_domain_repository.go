@@ -3,10 +3,10 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/Alfian57/belajar-golang/internal/database"
 	errs "github.com/Alfian57/belajar-golang/internal/errors"
-	"github.com/Alfian57/belajar-golang/internal/logger"
 	"github.com/Alfian57/belajar-golang/internal/model"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -62,6 +62,27 @@ func (r *BannedDomainRepository) Count(ctx context.Context) (int64, error) {
 	return count, err
 }
 
+// GetVersion returns the row count and latest update time of the table,
+// which together change whenever a banned domain is created, updated or deleted
+func (r *BannedDomainRepository) GetVersion(ctx context.Context) (int64, time.Time, error) {
+	var version struct {
+		Count       int64
+		LastUpdated *time.Time
+	}
+
+	err := r.db.WithContext(ctx).Model(&model.BannedDomain{}).
+		Select("COUNT(*) AS count, MAX(updated_at) AS last_updated").
+		Scan(&version).Error
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	if version.LastUpdated == nil {
+		return version.Count, time.Time{}, nil
+	}
+	return version.Count, *version.LastUpdated, nil
+}
+
 // CountWithFilter returns the total number of users matching the search criteria
 func (r *BannedDomainRepository) CountByUrl(ctx context.Context, search string) (int64, error) {
 	var count int64
@@ -85,10 +106,20 @@ func (r *BannedDomainRepository) Create(ctx context.Context, bannedDomain *model
 	bannedDomain.ID = uuid.New()
 
 	err := r.db.WithContext(ctx).Create(bannedDomain).Error
-	logger.Log.Debug(err)
 	return err
 }
 
+// CreateInBatches inserts all bannedDomains inside a single transaction, batchSize rows per statement
+func (r *BannedDomainRepository) CreateInBatches(ctx context.Context, bannedDomains []model.BannedDomain, batchSize int) error {
+	for i := range bannedDomains {
+		bannedDomains[i].ID = uuid.New()
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(bannedDomains, batchSize).Error
+	})
+}
+
 func (r *BannedDomainRepository) GetByID(ctx context.Context, id string) (model.BannedDomain, error) {
 	var bannedDomain model.BannedDomain
 
@@ -104,7 +135,7 @@ func (r *BannedDomainRepository) GetByID(ctx context.Context, id string) (model.
 }
 
 func (r *BannedDomainRepository) Update(ctx context.Context, bannedDomain *model.BannedDomain) error {
-	err := r.db.WithContext(ctx).Model(bannedDomain).Select("url").Updates(bannedDomain).Error
+	err := r.db.WithContext(ctx).Model(bannedDomain).Select("url", "rule_type").Updates(bannedDomain).Error
 	return err
 }
 
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Alfian57/belajar-golang/internal/database"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BannedDomainScanRepository struct {
+	db *gorm.DB
+}
+
+func NewBannedDomainScanRepository() *BannedDomainScanRepository {
+	return &BannedDomainScanRepository{db: database.DB}
+}
+
+// GetAllWithFilterPagination retrieves scans with an optional status filter, newest first
+func (r *BannedDomainScanRepository) GetAllWithFilterPagination(ctx context.Context, status string, limit int, offset int) ([]model.BannedDomainScan, error) {
+	var scans []model.BannedDomainScan
+
+	query := r.db.WithContext(ctx).Order("started_at DESC")
+
+	// Apply status filter
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	// Apply limit and offset
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	err := query.Find(&scans).Error
+	return scans, err
+}
+
+// CountByStatus returns the number of scans with the given status, or all scans when status is empty
+func (r *BannedDomainScanRepository) CountByStatus(ctx context.Context, status string) (int64, error) {
+	var count int64
+
+	query := r.db.WithContext(ctx).Model(&model.BannedDomainScan{})
+
+	// Apply status filter
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	err := query.Count(&count).Error
+	return count, err
+}
+
+func (r *BannedDomainScanRepository) Create(ctx context.Context, scan *model.BannedDomainScan) error {
+	scan.ID = uuid.New()
+
+	err := r.db.WithContext(ctx).Create(scan).Error
+	return err
+}
+
+func (r *BannedDomainScanRepository) GetByID(ctx context.Context, id string) (model.BannedDomainScan, error) {
+	var scan model.BannedDomainScan
+
+	err := r.db.WithContext(ctx).First(&scan, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return scan, errs.ErrBannedDomainScanNotFound
+		}
+		return scan, err
+	}
+
+	return scan, nil
+}
+
+func (r *BannedDomainScanRepository) Update(ctx context.Context, scan *model.BannedDomainScan) error {
+	err := r.db.WithContext(ctx).Model(scan).
+		Select("status", "scanned_count", "matched_count", "blocked_count", "error", "finished_at").
+		Updates(scan).Error
+	return err
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/database"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BotPatternRepository struct {
+	db *gorm.DB
+}
+
+func NewBotPatternRepository() *BotPatternRepository {
+	return &BotPatternRepository{db: database.DB}
+}
+
+// GetAllWithFilterPagination retrieves bot patterns with optional filters, ordering, and pagination
+func (r *BotPatternRepository) GetAllWithFilterPagination(ctx context.Context, search string, orderBy string, orderType string, limit int, offset int) ([]model.BotPattern, error) {
+	var botPatterns []model.BotPattern
+
+	query := r.db.WithContext(ctx)
+
+	// Apply search filter
+	if search != "" {
+		query = query.Where("pattern LIKE ?", "%"+search+"%")
+	}
+
+	// Apply ordering
+	if orderBy != "" && orderType != "" {
+		query = query.Order(orderBy + " " + orderType)
+	}
+
+	// Apply limit and offset
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if offset > 0 {
+		query = query.Offset(offset)
+	}
+
+	err := query.Find(&botPatterns).Error
+	return botPatterns, err
+}
+
+// GetAll retrieves all bot patterns without any filters
+func (r *BotPatternRepository) GetAll(ctx context.Context) ([]model.BotPattern, error) {
+	var botPatterns []model.BotPattern
+	err := r.db.WithContext(ctx).Find(&botPatterns).Error
+	return botPatterns, err
+}
+
+// GetVersion returns the row count and latest update time of the table,
+// which together change whenever a bot pattern is created, updated or deleted
+func (r *BotPatternRepository) GetVersion(ctx context.Context) (int64, time.Time, error) {
+	var version struct {
+		Count       int64
+		LastUpdated *time.Time
+	}
+
+	err := r.db.WithContext(ctx).Model(&model.BotPattern{}).
+		Select("COUNT(*) AS count, MAX(updated_at) AS last_updated").
+		Scan(&version).Error
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	if version.LastUpdated == nil {
+		return version.Count, time.Time{}, nil
+	}
+	return version.Count, *version.LastUpdated, nil
+}
+
+// CountByPattern returns the number of bot patterns matching the search criteria
+func (r *BotPatternRepository) CountByPattern(ctx context.Context, search string) (int64, error) {
+	var count int64
+
+	query := r.db.WithContext(ctx).Model(&model.BotPattern{})
+
+	// Apply search filter
+	if search != "" {
+		query = query.Where("pattern LIKE ?", "%"+search+"%")
+	}
+
+	err := query.Count(&count).Error
+	return count, err
+}
+
+func (r *BotPatternRepository) Create(ctx context.Context, botPattern *model.BotPattern) error {
+	botPattern.ID = uuid.New()
+
+	err := r.db.WithContext(ctx).Create(botPattern).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return errs.ErrBotPatternExist
+	}
+	return err
+}
+
+func (r *BotPatternRepository) GetByID(ctx context.Context, id string) (model.BotPattern, error) {
+	var botPattern model.BotPattern
+
+	err := r.db.WithContext(ctx).First(&botPattern, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return botPattern, errs.ErrBotPatternNotFound
+		}
+		return botPattern, err
+	}
+
+	return botPattern, nil
+}
+
+func (r *BotPatternRepository) Update(ctx context.Context, botPattern *model.BotPattern) error {
+	err := r.db.WithContext(ctx).Model(botPattern).Select("pattern", "description").Updates(botPattern).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return errs.ErrBotPatternExist
+	}
+	return err
+}
+
+func (r *BotPatternRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&model.BotPattern{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.ErrBotPatternNotFound
+	}
+
+	return nil
+}
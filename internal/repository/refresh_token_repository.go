@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/Alfian57/belajar-golang/internal/database"
 	errs "github.com/Alfian57/belajar-golang/internal/errors"
@@ -30,13 +31,13 @@ func (r *RefreshTokenRepository) Create(ctx context.Context, refreshToken *model
 	return nil
 }
 
-func (r *RefreshTokenRepository) GetByTokenHash(ctx context.Context, token string) (model.RefreshToken, error) {
+func (r *RefreshTokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (model.RefreshToken, error) {
 	var refreshToken model.RefreshToken
 
-	err := r.db.WithContext(ctx).First(&refreshToken, "token_hash = ?", token).Error
+	err := r.db.WithContext(ctx).First(&refreshToken, "token_hash = ?", tokenHash).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return refreshToken, errs.ErrTokenNotFound
+			return refreshToken, errs.ErrRefreshTokenNotFound
 		}
 		return refreshToken, err
 	}
@@ -44,12 +45,21 @@ func (r *RefreshTokenRepository) GetByTokenHash(ctx context.Context, token strin
 	return refreshToken, nil
 }
 
-func (r *RefreshTokenRepository) DeleteByTokenHash(ctx context.Context, refreshToken string) error {
-	result := r.db.WithContext(ctx).Delete(&model.RefreshToken{}, "token_hash = ?", refreshToken)
-
-	if result.RowsAffected == 0 {
-		return errs.ErrRefreshTokenNotFound
+// MarkRotated marks a token as exchanged. It returns false when the token was already rotated,
+// so that only one of two concurrent refreshes with the same token succeeds.
+func (r *RefreshTokenRepository) MarkRotated(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.RefreshToken{}).
+		Where("id = ? AND rotated_at IS NULL", id).
+		Update("rotated_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
 	}
 
-	return result.Error
+	return result.RowsAffected > 0, nil
+}
+
+// DeleteExpired removes the tokens that expired before now and returns how many were removed
+func (r *RefreshTokenRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Delete(&model.RefreshToken{}, "expires_at < ?", now.Unix())
+	return result.RowsAffected, result.Error
 }
@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Alfian57/belajar-golang/internal/database"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RetentionRunRepository struct {
+	db *gorm.DB
+}
+
+func NewRetentionRunRepository() *RetentionRunRepository {
+	return &RetentionRunRepository{db: database.DB}
+}
+
+func (r *RetentionRunRepository) Create(ctx context.Context, run *model.RetentionRun) error {
+	run.ID = uuid.New()
+
+	err := r.db.WithContext(ctx).Create(run).Error
+	return err
+}
+
+func (r *RetentionRunRepository) Update(ctx context.Context, run *model.RetentionRun) error {
+	err := r.db.WithContext(ctx).Model(run).
+		Select("status", "batch_count", "processed_count", "error", "finished_at").
+		Updates(run).Error
+	return err
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/database"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{db: database.DB}
+}
+
+// GetActiveByUserID retrieves the sessions of a user that haven't expired, most recently used first
+func (r *SessionRepository) GetActiveByUserID(ctx context.Context, userID string) ([]model.Session, error) {
+	var sessions []model.Session
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *SessionRepository) Create(ctx context.Context, session *model.Session) error {
+	session.ID = uuid.New()
+
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+func (r *SessionRepository) GetByID(ctx context.Context, id string) (model.Session, error) {
+	var session model.Session
+
+	err := r.db.WithContext(ctx).First(&session, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return session, errs.ErrSessionNotFound
+		}
+		return session, err
+	}
+
+	return session, nil
+}
+
+// UpdateUsage records the client and time of the last refresh and extends the session
+func (r *SessionRepository) UpdateUsage(ctx context.Context, session *model.Session) error {
+	return r.db.WithContext(ctx).Model(session).
+		Select("user_agent", "ip_address", "last_used_at", "expires_at").
+		Updates(session).Error
+}
+
+// Delete revokes a session together with its refresh tokens
+func (r *SessionRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Delete(&model.Session{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+func (r *SessionRepository) DeleteByIDAndUserID(ctx context.Context, id string, userID string) error {
+	result := r.db.WithContext(ctx).Delete(&model.Session{}, "id = ? AND user_id = ?", id, userID)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// DeleteByUserID revokes every session of a user and returns how many were revoked
+func (r *SessionRepository) DeleteByUserID(ctx context.Context, userID string) (int64, error) {
+	result := r.db.WithContext(ctx).Delete(&model.Session{}, "user_id = ?", userID)
+	return result.RowsAffected, result.Error
+}
+
+// DeleteExpired removes the sessions that expired before now and returns how many were removed
+func (r *SessionRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Delete(&model.Session{}, "expires_at < ?", now)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/database"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StatsPeriod splits a stats date range into the full UTC days between RollupFrom and RollupTo,
+// which are read from the daily rollups, and the rest of the range, which is read from the raw visits.
+// RollupFrom and RollupTo are equal when the whole range is read from the raw visits.
+type StatsPeriod struct {
+	From       time.Time
+	To         time.Time
+	RollupFrom time.Time
+	RollupTo   time.Time
+}
+
+// HasRollup reports whether part of the period is read from the daily rollups.
+func (p StatsPeriod) HasRollup() bool {
+	return p.RollupFrom.Before(p.RollupTo)
+}
+
+// RawStatsPeriod returns a period that is read from the raw visits only.
+func RawStatsPeriod(from time.Time, to time.Time) StatsPeriod {
+	return StatsPeriod{From: from, To: to, RollupFrom: to, RollupTo: to}
+}
+
+type StatsRollupRepository struct {
+	db *gorm.DB
+}
+
+func NewStatsRollupRepository() *StatsRollupRepository {
+	return &StatsRollupRepository{db: database.DB}
+}
+
+// GetRolledUpUntil returns the day after the last rolled up UTC day.
+// It returns false when no day was rolled up yet.
+func (r *StatsRollupRepository) GetRolledUpUntil(ctx context.Context) (time.Time, bool, error) {
+	var day sql.NullTime
+	err := r.db.WithContext(ctx).Model(&model.UrlStatsRollup{}).Select("MAX(day)").Scan(&day).Error
+	if err != nil || !day.Valid {
+		return time.Time{}, false, err
+	}
+
+	return day.Time.AddDate(0, 0, 1), true, nil
+}
+
+// GetFirstVisitDay returns the UTC day of the oldest recorded visit.
+// It returns false when no visit was recorded yet.
+func (r *StatsRollupRepository) GetFirstVisitDay(ctx context.Context) (time.Time, bool, error) {
+	var createdAt sql.NullTime
+	err := r.db.WithContext(ctx).Model(&model.URLVisitor{}).Select("MIN(created_at)").Scan(&createdAt).Error
+	if err != nil || !createdAt.Valid {
+		return time.Time{}, false, err
+	}
+
+	t := createdAt.Time
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), true, nil
+}
+
+// RollupDay replaces the rollups of a UTC day with the counts of the visits recorded on that day,
+// so that rolling up the same day again gives the same result. It returns the number of visits of the day.
+func (r *StatsRollupRepository) RollupDay(ctx context.Context, day time.Time) (int64, error) {
+	start := day.UTC()
+	end := start.AddDate(0, 0, 1)
+
+	// Iterate the dimensions in a stable order
+	dimensions := make([]string, 0, len(visitorBreakdownColumns))
+	for dimension := range visitorBreakdownColumns {
+		dimensions = append(dimensions, dimension)
+	}
+	sort.Strings(dimensions)
+
+	var visitCount int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("day = ?", start).Delete(&model.UrlStatsDaily{}).Error; err != nil {
+			return err
+		}
+
+		err := tx.Exec(`INSERT INTO url_stats_daily (url_id, day, dimension, value, is_bot, count)
+			SELECT url_visitors.url_id, ?, ?, '', url_visitors.is_bot, COUNT(*) FROM url_visitors
+			WHERE url_visitors.created_at >= ? AND url_visitors.created_at < ?
+			GROUP BY url_visitors.url_id, url_visitors.is_bot`, start, model.UrlStatsDimensionTotal, start, end).Error
+		if err != nil {
+			return err
+		}
+
+		for _, dimension := range dimensions {
+			column := visitorBreakdownColumns[dimension]
+			err := tx.Exec(`INSERT INTO url_stats_daily (url_id, day, dimension, value, is_bot, count)
+				SELECT url_visitors.url_id, ?, ?, `+column+`, url_visitors.is_bot, COUNT(*) FROM url_visitors
+				WHERE url_visitors.created_at >= ? AND url_visitors.created_at < ?
+				GROUP BY url_visitors.url_id, url_visitors.is_bot, `+column, start, dimension, start, end).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		err = tx.Model(&model.UrlStatsDaily{}).
+			Select("COALESCE(SUM(count), 0)::BIGINT").
+			Where("day = ? AND dimension = ?", start, model.UrlStatsDimensionTotal).
+			Scan(&visitCount).Error
+		if err != nil {
+			return err
+		}
+
+		rollup := model.UrlStatsRollup{Day: start, VisitCount: visitCount}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "day"}},
+			DoUpdates: clause.AssignmentColumns([]string{"visit_count", "updated_at"}),
+		}).Create(&rollup).Error
+	})
+
+	return visitCount, err
+}
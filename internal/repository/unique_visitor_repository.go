@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/database"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/Alfian57/belajar-golang/internal/utils/hll"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UniqueVisitorRepository struct {
+	db *gorm.DB
+}
+
+func NewUniqueVisitorRepository() *UniqueVisitorRepository {
+	return &UniqueVisitorRepository{db: database.DB}
+}
+
+// EachUrlSketch calls fn for every daily url sketch from fromDay up to, but not including, toDay.
+// Sketches are filtered by url when urlID is given and by the owner of the url when userID is given.
+// Bot sketches are left out unless includeBots is set. Rows are read from a cursor one at a time,
+// and iteration stops at the first error returned by fn.
+func (r *UniqueVisitorRepository) EachUrlSketch(ctx context.Context, urlID string, userID string, fromDay time.Time, toDay time.Time, includeBots bool, fn func([]byte) error) error {
+	query := r.db.WithContext(ctx).Model(&model.UrlUniqueVisitorsDaily{}).
+		Select("url_unique_visitors_daily.sketch").
+		Where("url_unique_visitors_daily.day >= ? AND url_unique_visitors_daily.day < ?", fromDay, toDay)
+
+	// Apply url filter
+	if urlID != "" {
+		query = query.Where("url_unique_visitors_daily.url_id = ?", urlID)
+	}
+
+	// Apply owner filter
+	if userID != "" {
+		query = query.Joins("JOIN urls ON urls.id = url_unique_visitors_daily.url_id").Where("urls.user_id = ?", userID)
+	}
+
+	// Apply bot filter
+	if !includeBots {
+		query = query.Where("url_unique_visitors_daily.is_bot = ?", false)
+	}
+
+	return eachSketch(query, fn)
+}
+
+// EachSketch calls fn for every daily sketch of all urls from fromDay up to, but not including, toDay.
+// Bot sketches are left out unless includeBots is set.
+func (r *UniqueVisitorRepository) EachSketch(ctx context.Context, fromDay time.Time, toDay time.Time, includeBots bool, fn func([]byte) error) error {
+	query := r.db.WithContext(ctx).Model(&model.UniqueVisitorsDaily{}).
+		Select("sketch").
+		Where("day >= ? AND day < ?", fromDay, toDay)
+
+	// Apply bot filter
+	if !includeBots {
+		query = query.Where("is_bot = ?", false)
+	}
+
+	return eachSketch(query, fn)
+}
+
+// MergeUrlSketch merges sketch into the sketch of the url on the given UTC day.
+func (r *UniqueVisitorRepository) MergeUrlSketch(ctx context.Context, urlID uuid.UUID, day time.Time, isBot bool, sketch *hll.Sketch) error {
+	row := model.UrlUniqueVisitorsDaily{UrlID: urlID, Day: day, IsBot: isBot}
+	conditions := map[string]any{"url_id": urlID, "day": day, "is_bot": isBot}
+	return r.mergeSketch(ctx, &row, &row.Sketch, conditions, sketch)
+}
+
+// MergeSketch merges sketch into the sketch of all urls on the given UTC day.
+func (r *UniqueVisitorRepository) MergeSketch(ctx context.Context, day time.Time, isBot bool, sketch *hll.Sketch) error {
+	row := model.UniqueVisitorsDaily{Day: day, IsBot: isBot}
+	conditions := map[string]any{"day": day, "is_bot": isBot}
+	return r.mergeSketch(ctx, &row, &row.Sketch, conditions, sketch)
+}
+
+// mergeSketch creates the row when it is missing, then locks it so that concurrent merges don't lose updates.
+// The row is matched by conditions rather than by its primary key, since GORM leaves zero values such as
+// a false is_bot out of the key conditions. stored points at the sketch field of the row.
+func (r *UniqueVisitorRepository) mergeSketch(ctx context.Context, row any, stored *[]byte, conditions map[string]any, sketch *hll.Sketch) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		*stored = hll.New().Bytes()
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(row).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where(conditions).Take(row).Error; err != nil {
+			return err
+		}
+
+		merged, err := hll.FromBytes(*stored)
+		if err != nil {
+			return err
+		}
+		merged.Merge(sketch)
+
+		return tx.Model(row).Where(conditions).UpdateColumns(map[string]any{"sketch": merged.Bytes(), "updated_at": time.Now()}).Error
+	})
+}
+
+func eachSketch(query *gorm.DB, fn func([]byte) error) error {
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sketch []byte
+		if err := rows.Scan(&sketch); err != nil {
+			return err
+		}
+		if err := fn(sketch); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -21,10 +21,16 @@ func NewUrlRepository() *UrlRepository {
 	return &UrlRepository{db: database.DB}
 }
 
-func (r *UrlRepository) GetAllWithFilterPagination(ctx context.Context, search string, orderBy string, orderType string, limit int, offset int) ([]model.Url, error) {
+func (r *UrlRepository) GetAllWithFilterPagination(ctx context.Context, userID string, search string, orderBy string, orderType string, limit int, offset int) ([]model.Url, error) {
 	var urls []model.Url
 
-	query := r.db.WithContext(ctx)
+	// Archived urls are kept for retention only and are not listed
+	query := r.db.WithContext(ctx).Where("archived_at IS NULL")
+
+	// Apply owner filter
+	if userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
 
 	// Apply search filter
 	if search != "" {
@@ -60,10 +66,15 @@ func (r *UrlRepository) Count(ctx context.Context) (int64, error) {
 	return count, err
 }
 
-func (r *UrlRepository) CountByShortUrl(ctx context.Context, search string) (int64, error) {
+func (r *UrlRepository) CountByShortUrl(ctx context.Context, userID string, search string) (int64, error) {
 	var count int64
 
-	query := r.db.WithContext(ctx).Model(&model.Url{})
+	query := r.db.WithContext(ctx).Model(&model.Url{}).Where("archived_at IS NULL")
+
+	// Apply owner filter
+	if userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
 
 	// Apply search filter
 	if search != "" {
@@ -83,6 +94,9 @@ func (r *UrlRepository) Create(ctx context.Context, url *model.Url) error {
 
 	err := r.db.WithContext(ctx).Create(url).Error
 	logger.Log.Debug(err)
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return errs.ErrShortUrlExist
+	}
 	return err
 }
 
@@ -100,22 +114,127 @@ func (r *UrlRepository) GetByID(ctx context.Context, id string) (model.Url, erro
 	return url, nil
 }
 
-func (r *UrlRepository) GetByExpiredMoreThan(ctx context.Context, expiredTime time.Time) ([]model.Url, error) {
-	var urls []model.Url
+func (r *UrlRepository) GetByIDAndUserID(ctx context.Context, id string, userID string) (model.Url, error) {
+	var url model.Url
 
-	err := r.db.WithContext(ctx).Where("expired_at > ?", expiredTime).Find(&urls).Error
+	err := r.db.WithContext(ctx).First(&url, "id = ? AND user_id = ?", id, userID).Error
 	if err != nil {
-		return nil, err
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return url, errs.ErrUrlNotFound
+		}
+		return url, err
 	}
-	if len(urls) == 0 {
-		return nil, errs.ErrUrlNotFound
+
+	return url, nil
+}
+
+func (r *UrlRepository) GetByShortUrl(ctx context.Context, shortUrl string) (model.Url, error) {
+	var url model.Url
+
+	err := r.db.WithContext(ctx).First(&url, "short_url = ?", shortUrl).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return url, errs.ErrUrlNotFound
+		}
+		return url, err
 	}
 
-	return urls, nil
+	return url, nil
+}
+
+// GetBatchAfterID returns up to limit urls ordered by ID, starting after afterID.
+// Passing uuid.Nil starts from the beginning of the table.
+func (r *UrlRepository) GetBatchAfterID(ctx context.Context, afterID uuid.UUID, limit int) ([]model.Url, error) {
+	var urls []model.Url
+
+	err := r.db.WithContext(ctx).Where("id > ?", afterID).Order("id").Limit(limit).Find(&urls).Error
+	return urls, err
+}
+
+// BlockByIDs flags the given urls as blocked by a banned domain rule.
+// Urls that are already blocked are left untouched. It returns the number of urls blocked.
+func (r *UrlRepository) BlockByIDs(ctx context.Context, ids []uuid.UUID, bannedDomainID uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&model.Url{}).
+		Where("id IN ? AND is_blocked = ?", ids, false).
+		Updates(map[string]any{"is_blocked": true, "blocked_by_id": bannedDomainID})
+
+	return result.RowsAffected, result.Error
+}
+
+// GetBlockedBatchAfterID returns up to limit urls blocked by a banned domain rule, ordered by ID
+// and starting after afterID. A nil bannedDomainID returns the blocked urls whose rule was deleted.
+func (r *UrlRepository) GetBlockedBatchAfterID(ctx context.Context, bannedDomainID *uuid.UUID, afterID uuid.UUID, limit int) ([]model.Url, error) {
+	var urls []model.Url
+
+	query := r.db.WithContext(ctx).Where("is_blocked = ? AND id > ?", true, afterID)
+	if bannedDomainID != nil {
+		query = query.Where("blocked_by_id = ?", *bannedDomainID)
+	} else {
+		query = query.Where("blocked_by_id IS NULL")
+	}
+
+	err := query.Order("id").Limit(limit).Find(&urls).Error
+	return urls, err
+}
+
+// ReassignBlockByIDs attributes blocked urls to another banned domain rule.
+func (r *UrlRepository) ReassignBlockByIDs(ctx context.Context, ids []uuid.UUID, bannedDomainID uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&model.Url{}).
+		Where("id IN ? AND is_blocked = ?", ids, true).
+		Update("blocked_by_id", bannedDomainID)
+
+	return result.RowsAffected, result.Error
+}
+
+// UnblockByIDs clears the blocked flag of the given urls. It returns the number of urls unblocked.
+func (r *UrlRepository) UnblockByIDs(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&model.Url{}).
+		Where("id IN ? AND is_blocked = ?", ids, true).
+		Updates(map[string]any{"is_blocked": false, "blocked_by_id": nil})
+
+	return result.RowsAffected, result.Error
+}
+
+// IncrementClickCount counts a click on the url unless it already reached its click limit.
+// The check and the increment happen in a single statement so concurrent visits can't overshoot the limit.
+// It returns false when the limit was already reached.
+func (r *UrlRepository) IncrementClickCount(ctx context.Context, id uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.Url{}).
+		Where("id = ? AND (max_clicks IS NULL OR click_count < max_clicks)", id).
+		UpdateColumn("click_count", gorm.Expr("click_count + 1"))
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteExpiredBatch deletes up to limit urls that expired before cutoff.
+// It returns the number of urls deleted, which is less than limit once no expired urls are left.
+func (r *UrlRepository) DeleteExpiredBatch(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	batch := r.db.Model(&model.Url{}).Select("id").
+		Where("expired_at < ?", cutoff).
+		Order("expired_at").Limit(limit)
+
+	result := r.db.WithContext(ctx).Where("id IN (?)", batch).Delete(&model.Url{})
+	return result.RowsAffected, result.Error
+}
+
+// ArchiveExpiredBatch marks up to limit urls that expired before cutoff as archived.
+// Urls that are already archived are skipped. It returns the number of urls archived.
+func (r *UrlRepository) ArchiveExpiredBatch(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	batch := r.db.Model(&model.Url{}).Select("id").
+		Where("expired_at < ? AND archived_at IS NULL", cutoff).
+		Order("expired_at").Limit(limit)
+
+	result := r.db.WithContext(ctx).Model(&model.Url{}).
+		Where("id IN (?)", batch).
+		UpdateColumn("archived_at", time.Now())
+	return result.RowsAffected, result.Error
 }
 
 func (r *UrlRepository) Update(ctx context.Context, url *model.Url) error {
-	err := r.db.WithContext(ctx).Model(url).Select("short_url", "long_url", "user_id").Updates(url).Error
+	err := r.db.WithContext(ctx).Model(url).Select("short_url", "long_url", "user_id", "active_from", "expired_at", "max_clicks", "password", "is_blocked", "blocked_by_id").Updates(url).Error
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return errs.ErrShortUrlExist
+	}
 	return err
 }
 
@@ -131,3 +250,16 @@ func (r *UrlRepository) Delete(ctx context.Context, id string) error {
 
 	return nil
 }
+
+func (r *UrlRepository) DeleteByIDAndUserID(ctx context.Context, id string, userID string) error {
+	result := r.db.WithContext(ctx).Delete(&model.Url{}, "id = ? AND user_id = ?", id, userID)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.ErrUrlNotFound
+	}
+
+	return nil
+}
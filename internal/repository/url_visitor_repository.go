@@ -2,14 +2,31 @@ package repository
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/Alfian57/belajar-golang/internal/database"
+	"github.com/Alfian57/belajar-golang/internal/dto"
 	"github.com/Alfian57/belajar-golang/internal/logger"
 	"github.com/Alfian57/belajar-golang/internal/model"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// visitorBreakdownColumns maps the supported breakdown dimensions to the expression they are grouped by.
+var visitorBreakdownColumns = map[string]string{
+	"referrer":        "url_visitors.referrer_host",
+	"device":          "url_visitors.device_type",
+	"os":              "url_visitors.os_family",
+	"os_version":      "TRIM(CONCAT(url_visitors.os_family, ' ', url_visitors.os_version))",
+	"browser":         "url_visitors.browser_family",
+	"browser_version": "TRIM(CONCAT(url_visitors.browser_family, ' ', url_visitors.browser_version))",
+	"country":         "url_visitors.country",
+	"region":          "url_visitors.region",
+	"city":            "url_visitors.city",
+}
+
 type UrlVisitorRepository struct {
 	db *gorm.DB
 }
@@ -18,16 +35,206 @@ func NewUrlVisitorRepository() *UrlVisitorRepository {
 	return &UrlVisitorRepository{db: database.DB}
 }
 
-func (r *UrlVisitorRepository) Count(ctx context.Context) (int64, error) {
+// CountInPeriod counts the visitors over period. Visitors are filtered by url when urlID is given and by
+// the owner of the url when userID is given. Bot visits are left out unless includeBots is set.
+func (r *UrlVisitorRepository) CountInPeriod(ctx context.Context, urlID string, userID string, period StatsPeriod, includeBots bool) (int64, error) {
+	counts := r.db.Model(&model.URLVisitor{}).
+		Select("COUNT(*) AS count").
+		Where(rawStatsCondition(period))
+
+	// Apply url filter
+	if urlID != "" {
+		counts = counts.Where("url_visitors.url_id = ?", urlID)
+	}
+
+	// Apply owner filter
+	if userID != "" {
+		counts = counts.Joins("JOIN urls ON urls.id = url_visitors.url_id").Where("urls.user_id = ?", userID)
+	}
+
+	// Apply bot filter
+	if !includeBots {
+		counts = counts.Where("url_visitors.is_bot = ?", false)
+	}
+
+	if period.HasRollup() {
+		rollup := r.db.Model(&model.UrlStatsDaily{}).
+			Select("SUM(url_stats_daily.count) AS count").
+			Where("url_stats_daily.dimension = ? AND url_stats_daily.day >= ? AND url_stats_daily.day < ?", model.UrlStatsDimensionTotal, period.RollupFrom, period.RollupTo)
+
+		// Apply url filter
+		if urlID != "" {
+			rollup = rollup.Where("url_stats_daily.url_id = ?", urlID)
+		}
+
+		// Apply owner filter
+		if userID != "" {
+			rollup = rollup.Joins("JOIN urls ON urls.id = url_stats_daily.url_id").Where("urls.user_id = ?", userID)
+		}
+
+		// Apply bot filter
+		if !includeBots {
+			rollup = rollup.Where("url_stats_daily.is_bot = ?", false)
+		}
+
+		counts = r.db.Raw("? UNION ALL ?", counts, rollup)
+	}
+
 	var count int64
-	err := r.db.WithContext(ctx).Model(&model.URLVisitor{}).Count(&count).Error
+	err := r.db.WithContext(ctx).Table("(?) AS counts", counts).
+		Select("COALESCE(SUM(count), 0)::BIGINT").
+		Scan(&count).Error
 	return count, err
 }
 
-func (r *UrlVisitorRepository) CountByUrlID(ctx context.Context, urlID string) (int64, error) {
-	var count int64
-	err := r.db.WithContext(ctx).Model(&model.URLVisitor{}).Where("url_id = ?", urlID).Count(&count).Error
-	return count, err
+// CountGroupedBy counts visitors over period per value of the given dimension and returns
+// the limit most common values. Visitors are filtered by url when urlID is given and by the owner
+// of the url when userID is given. Bot visits are left out unless includeBots is set.
+func (r *UrlVisitorRepository) CountGroupedBy(ctx context.Context, urlID string, userID string, dimension string, period StatsPeriod, includeBots bool, limit int) ([]dto.VisitorBreakdownItem, error) {
+	column, ok := visitorBreakdownColumns[dimension]
+	if !ok {
+		return nil, fmt.Errorf("unknown visitor breakdown dimension %q", dimension)
+	}
+
+	raw := r.db.Model(&model.URLVisitor{}).
+		Select(column + " AS value, COUNT(*) AS count").
+		Where(rawStatsCondition(period))
+
+	// Apply url filter
+	if urlID != "" {
+		raw = raw.Where("url_visitors.url_id = ?", urlID)
+	}
+
+	// Apply owner filter
+	if userID != "" {
+		raw = raw.Joins("JOIN urls ON urls.id = url_visitors.url_id").Where("urls.user_id = ?", userID)
+	}
+
+	// Apply bot filter
+	if !includeBots {
+		raw = raw.Where("url_visitors.is_bot = ?", false)
+	}
+
+	counts := raw.Group("value")
+	if period.HasRollup() {
+		rollup := r.db.Model(&model.UrlStatsDaily{}).
+			Select("url_stats_daily.value, SUM(url_stats_daily.count) AS count").
+			Where("url_stats_daily.dimension = ? AND url_stats_daily.day >= ? AND url_stats_daily.day < ?", dimension, period.RollupFrom, period.RollupTo)
+
+		// Apply url filter
+		if urlID != "" {
+			rollup = rollup.Where("url_stats_daily.url_id = ?", urlID)
+		}
+
+		// Apply owner filter
+		if userID != "" {
+			rollup = rollup.Joins("JOIN urls ON urls.id = url_stats_daily.url_id").Where("urls.user_id = ?", userID)
+		}
+
+		// Apply bot filter
+		if !includeBots {
+			rollup = rollup.Where("url_stats_daily.is_bot = ?", false)
+		}
+
+		counts = r.db.Raw("? UNION ALL ?", counts, rollup.Group("url_stats_daily.value"))
+	}
+
+	var items []dto.VisitorBreakdownItem
+	err := r.db.WithContext(ctx).Table("(?) AS counts", counts).
+		Select("value, SUM(count)::BIGINT AS count").
+		Group("value").
+		Order("count DESC, value").
+		Limit(limit).
+		Scan(&items).Error
+	return items, err
+}
+
+// CountByUrlIDPerInterval counts the visitors of a url over period, bucketed by interval.
+// Buckets are truncated in the given timezone and only buckets with visits are returned, oldest first.
+// The created_at column holds UTC wall clock time, and the returned buckets hold the wall clock time of timezone.
+// The rolled up part of period is only correct for daily or weekly buckets in UTC.
+// Bot visits are left out unless includeBots is set.
+func (r *UrlVisitorRepository) CountByUrlIDPerInterval(ctx context.Context, urlID string, interval string, timezone string, period StatsPeriod, includeBots bool) ([]dto.TimeseriesPoint, error) {
+	counts := r.db.Model(&model.URLVisitor{}).
+		Select("date_trunc(?, created_at AT TIME ZONE 'UTC' AT TIME ZONE ?) AS bucket, COUNT(*) AS count", interval, timezone).
+		Where("url_id = ?", urlID).
+		Where(rawStatsCondition(period))
+
+	// Apply bot filter
+	if !includeBots {
+		counts = counts.Where("is_bot = ?", false)
+	}
+	counts = counts.Group("bucket")
+
+	if period.HasRollup() {
+		rollup := r.db.Model(&model.UrlStatsDaily{}).
+			Select("date_trunc(?, day::timestamp) AS bucket, SUM(count) AS count", interval).
+			Where("url_id = ? AND dimension = ? AND day >= ? AND day < ?", urlID, model.UrlStatsDimensionTotal, period.RollupFrom, period.RollupTo)
+
+		// Apply bot filter
+		if !includeBots {
+			rollup = rollup.Where("is_bot = ?", false)
+		}
+
+		counts = r.db.Raw("? UNION ALL ?", counts, rollup.Group("bucket"))
+	}
+
+	var points []dto.TimeseriesPoint
+	err := r.db.WithContext(ctx).Table("(?) AS counts", counts).
+		Select("bucket, SUM(count)::BIGINT AS count").
+		Group("bucket").
+		Order("bucket").
+		Scan(&points).Error
+	return points, err
+}
+
+// EachInRange calls fn for every visitor recorded between from and to, oldest first.
+// Rows are read from a cursor one at a time instead of being loaded at once, and iteration stops at
+// the first error returned by fn. Visitors are filtered by url when urlID is given and by the owner
+// of the url when userID is given. Bot visits are left out unless includeBots is set.
+func (r *UrlVisitorRepository) EachInRange(ctx context.Context, urlID string, userID string, from time.Time, to time.Time, includeBots bool, fn func(model.URLVisitor) error) error {
+	query := r.db.WithContext(ctx).Model(&model.URLVisitor{}).
+		Select("url_visitors.*").
+		Where("url_visitors.created_at >= ? AND url_visitors.created_at < ?", from.UTC(), to.UTC())
+
+	// Apply url filter
+	if urlID != "" {
+		query = query.Where("url_visitors.url_id = ?", urlID)
+	}
+
+	// Apply owner filter
+	if userID != "" {
+		query = query.Joins("JOIN urls ON urls.id = url_visitors.url_id").Where("urls.user_id = ?", userID)
+	}
+
+	// Apply bot filter
+	if !includeBots {
+		query = query.Where("url_visitors.is_bot = ?", false)
+	}
+
+	rows, err := query.Order("url_visitors.created_at, url_visitors.id").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var urlVisitor model.URLVisitor
+		if err := r.db.ScanRows(rows, &urlVisitor); err != nil {
+			return err
+		}
+		if err := fn(urlVisitor); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// rawStatsCondition matches the visits of the part of period that isn't rolled up.
+func rawStatsCondition(period StatsPeriod) clause.Expr {
+	return gorm.Expr("((url_visitors.created_at >= ? AND url_visitors.created_at < ?) OR (url_visitors.created_at >= ? AND url_visitors.created_at < ?))",
+		period.From.UTC(), period.RollupFrom.UTC(), period.RollupTo.UTC(), period.To.UTC())
 }
 
 func (r *UrlVisitorRepository) Create(ctx context.Context, urlVisitor *model.URLVisitor) error {
@@ -37,3 +244,12 @@ func (r *UrlVisitorRepository) Create(ctx context.Context, urlVisitor *model.URL
 	logger.Log.Debug(err)
 	return err
 }
+
+// CreateBatch inserts the visitors with a single statement per batch of at most len(urlVisitors) rows.
+func (r *UrlVisitorRepository) CreateBatch(ctx context.Context, urlVisitors []model.URLVisitor) error {
+	for i := range urlVisitors {
+		urlVisitors[i].ID = uuid.New()
+	}
+
+	return r.db.WithContext(ctx).CreateInBatches(urlVisitors, len(urlVisitors)).Error
+}
@@ -136,6 +136,23 @@ func (r *UserRepository) Update(ctx context.Context, user *model.User) error {
 	return err
 }
 
+// Ban flags a user as banned and increments its token version, which invalidates its access tokens
+func (r *UserRepository) Ban(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Model(&model.User{}).Where("id = ?", id).Updates(map[string]any{
+		"is_banned":     true,
+		"token_version": gorm.Expr("token_version + 1"),
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errs.ErrUserNotFound
+	}
+
+	return nil
+}
+
 func (r *UserRepository) Delete(ctx context.Context, id string) error {
 	result := r.db.WithContext(ctx).Delete(&model.User{}, "id = ?", id)
 	if result.Error != nil {
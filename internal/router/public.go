@@ -0,0 +1,15 @@
+package router
+
+import (
+	"github.com/Alfian57/belajar-golang/internal/di"
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterPublicRoute(router *gin.RouterGroup) {
+
+	redirectHandler := di.InitializeRedirectHandler()
+
+	router.GET("/:code", redirectHandler.Redirect)
+	router.HEAD("/:code", redirectHandler.Redirect)
+	router.POST("/:code", redirectHandler.Unlock)
+}
@@ -12,5 +12,7 @@ func NewRouter() *gin.Engine {
 	v1 := api.Group("v1")
 	RegisterV1Route(v1)
 
+	RegisterPublicRoute(&router.RouterGroup)
+
 	return router
 }
@@ -3,6 +3,7 @@ package router
 import (
 	"github.com/Alfian57/belajar-golang/internal/di"
 	"github.com/Alfian57/belajar-golang/internal/middleware"
+	"github.com/Alfian57/belajar-golang/internal/model"
 	"github.com/gin-gonic/gin"
 )
 
@@ -13,13 +14,54 @@ func RegisterV1Route(router *gin.RouterGroup) {
 	urlHandler := di.InitializeUrlHandler()
 	urlVisitorHandler := di.InitializeUrlVisitorHandler()
 	bannedDomainHandler := di.InitializeBannedDomainHandler()
+	bannedDomainScanHandler := di.InitializeBannedDomainScanHandler()
+	botPatternHandler := di.InitializeBotPatternHandler()
+	apiKeyHandler := di.InitializeApiKeyHandler()
+	sessionHandler := di.InitializeSessionHandler()
 
 	router.POST("/login", authHandler.Login)
 	router.POST("/register", authHandler.Register)
 	router.POST("/refresh", middleware.AuthMiddleware(), authHandler.Refresh)
+	router.POST("/token/refresh", authHandler.RefreshToken)
 	router.POST("/logout", middleware.AuthMiddleware(), authHandler.Logout)
 
-	admin := router.Group("admin", middleware.AuthMiddleware(), middleware.AdminMiddleware())
+	urlsRead := middleware.ScopeMiddleware(model.ApiKeyScopeUrlsRead)
+	urlsWrite := middleware.ScopeMiddleware(model.ApiKeyScopeUrlsWrite)
+	statsRead := middleware.ScopeMiddleware(model.ApiKeyScopeStatsRead)
+
+	me := router.Group("me", middleware.AuthMiddleware())
+	me.GET("/stats/count", statsRead, urlVisitorHandler.CountMyVisitors)
+	me.GET("/stats/breakdown", statsRead, urlVisitorHandler.GetMyBreakdown)
+	me.GET("/visitors/export", statsRead, urlVisitorHandler.ExportMyVisitors)
+
+	myUrls := me.Group("urls")
+	{
+		myUrls.GET("/", urlsRead, urlHandler.GetMyUrls)
+		myUrls.POST("/", urlsWrite, urlHandler.CreateMyUrl)
+		myUrls.GET("/:id", urlsRead, urlHandler.GetMyUrlByID)
+		myUrls.PUT("/:id", urlsWrite, urlHandler.UpdateMyUrl)
+		myUrls.DELETE("/:id", urlsWrite, urlHandler.DeleteMyUrl)
+		myUrls.GET("/:id/stats/count", statsRead, urlVisitorHandler.CountMyUrlVisitors)
+		myUrls.GET("/:id/stats/timeseries", statsRead, urlVisitorHandler.GetMyUrlTimeseries)
+		myUrls.GET("/:id/stats/breakdown", statsRead, urlVisitorHandler.GetMyUrlBreakdown)
+		myUrls.GET("/:id/visitors/export", statsRead, urlVisitorHandler.ExportMyUrlVisitors)
+	}
+
+	myApiKeys := me.Group("api-keys", middleware.ScopeMiddleware(model.ApiKeyScopeApiKeys))
+	{
+		myApiKeys.GET("/", apiKeyHandler.GetMyApiKeys)
+		myApiKeys.POST("/", apiKeyHandler.CreateMyApiKey)
+		myApiKeys.DELETE("/:id", apiKeyHandler.DeleteMyApiKey)
+	}
+
+	mySessions := me.Group("sessions", middleware.ScopeMiddleware(model.ApiKeyScopeSessions))
+	{
+		mySessions.GET("/", sessionHandler.GetMySessions)
+		mySessions.DELETE("/", sessionHandler.DeleteMySessions)
+		mySessions.DELETE("/:id", sessionHandler.DeleteMySession)
+	}
+
+	admin := router.Group("admin", middleware.AuthMiddleware(), middleware.AdminMiddleware(), middleware.ScopeMiddleware(model.ApiKeyScopeAdmin))
 
 	users := admin.Group("users")
 	{
@@ -30,6 +72,10 @@ func RegisterV1Route(router *gin.RouterGroup) {
 		users.DELETE("/:id", userHandler.DeleteUser)
 		users.GET("/count", userHandler.CountAllUsers)
 		users.POST("/:id/banned", userHandler.BannedUser)
+		users.GET("/:id/sessions", sessionHandler.GetUserSessions)
+		users.DELETE("/:id/sessions", sessionHandler.DeleteUserSessions)
+		users.GET("/:id/stats/breakdown", urlVisitorHandler.GetUserBreakdown)
+		users.GET("/:id/visitors/export", urlVisitorHandler.ExportUserVisitors)
 	}
 
 	urls := admin.Group("urls")
@@ -39,7 +85,11 @@ func RegisterV1Route(router *gin.RouterGroup) {
 		urls.GET("/:id", urlHandler.GetUrlByID)
 		urls.PUT("/:id", urlHandler.UpdateUrl)
 		urls.DELETE("/:id", urlHandler.DeleteUrl)
+		urls.POST("/:id/unblock", urlHandler.UnblockUrl)
 		urls.GET("/count", urlHandler.CountAllUrl)
+		urls.GET("/:id/stats/timeseries", urlVisitorHandler.GetUrlTimeseries)
+		urls.GET("/:id/stats/breakdown", urlVisitorHandler.GetUrlBreakdown)
+		urls.GET("/:id/visitors/export", urlVisitorHandler.ExportUrlVisitors)
 	}
 
 	urlsVisitor := admin.Group("urls-visitors")
@@ -52,8 +102,20 @@ func RegisterV1Route(router *gin.RouterGroup) {
 	{
 		bannedDomain.GET("/", bannedDomainHandler.GetAllBannedDomains)
 		bannedDomain.POST("/", bannedDomainHandler.CreateBannedDomain)
+		bannedDomain.POST("/import", bannedDomainHandler.ImportBannedDomains)
 		bannedDomain.PUT("/:id", bannedDomainHandler.UpdateBannedDomain)
 		bannedDomain.DELETE("/:id", bannedDomainHandler.DeleteBannedDomain)
+		bannedDomain.POST("/:id/scan", bannedDomainScanHandler.ScanBannedDomain)
+		bannedDomain.GET("/scans", bannedDomainScanHandler.GetAllScans)
+		bannedDomain.GET("/scans/:id", bannedDomainScanHandler.GetScanByID)
+	}
+
+	botPatterns := admin.Group("bot-patterns")
+	{
+		botPatterns.GET("/", botPatternHandler.GetAllBotPatterns)
+		botPatterns.POST("/", botPatternHandler.CreateBotPattern)
+		botPatterns.PUT("/:id", botPatternHandler.UpdateBotPattern)
+		botPatterns.DELETE("/:id", botPatternHandler.DeleteBotPattern)
 	}
 
 	// *
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/constants"
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/Alfian57/belajar-golang/internal/repository"
+	"github.com/Alfian57/belajar-golang/internal/utils/apikey"
+	"github.com/google/uuid"
+)
+
+type ApiKeyService struct {
+	apiKeyRepository *repository.ApiKeyRepository
+	userRepository   *repository.UserRepository
+}
+
+func NewApiKeyService(apiKeyRepository *repository.ApiKeyRepository, userRepository *repository.UserRepository) *ApiKeyService {
+	return &ApiKeyService{
+		apiKeyRepository: apiKeyRepository,
+		userRepository:   userRepository,
+	}
+}
+
+// GetUserApiKeys retrieves the keys of a user, newest first.
+func (s *ApiKeyService) GetUserApiKeys(ctx context.Context, userID uuid.UUID) ([]model.ApiKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	apiKeys, err := s.apiKeyRepository.GetAllByUserID(ctx, userID.String())
+	if err != nil {
+		logger.Log.Errorw("failed to retrieve api keys", "user_id", userID, "error", err)
+		return nil, errs.NewAppError(500, "failed to retrieve api keys", err)
+	}
+
+	return apiKeys, nil
+}
+
+// CreateUserApiKey mints a new key for user. The key itself is returned once and only its hash is stored.
+// A key created without scopes gets the default scopes, and a request authenticated with an api key
+// can only mint keys with a subset of its scopes.
+func (s *ApiKeyService) CreateUserApiKey(ctx context.Context, user model.User, currentKey *model.ApiKey, request dto.CreateApiKeyRequest) (dto.CreatedApiKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	scopes := normalizeApiKeyScopes(request.Scopes)
+	if err := validateApiKeyScopes(user, currentKey, scopes); err != nil {
+		return dto.CreatedApiKey{}, err
+	}
+	if len(scopes) == 0 {
+		scopes = slices.Clone(model.ApiKeyDefaultScopes)
+	}
+
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		fieldError := errs.NewFieldError("expires_at", "expires at must be in the future")
+		return dto.CreatedApiKey{}, errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+
+	count, err := s.apiKeyRepository.CountByUserID(ctx, user.ID.String())
+	if err != nil {
+		logger.Log.Errorw("failed to count api keys", "user_id", user.ID, "error", err)
+		return dto.CreatedApiKey{}, errs.NewAppError(500, "failed to create api key", err)
+	}
+	if count >= constants.MaxApiKeysPerUser {
+		return dto.CreatedApiKey{}, errs.NewAppError(http.StatusUnprocessableEntity, "api key limit reached, revoke an unused key first", nil)
+	}
+
+	key, err := apikey.Generate()
+	if err != nil {
+		logger.Log.Errorw("failed to generate api key", "error", err)
+		return dto.CreatedApiKey{}, errs.NewAppError(500, "failed to create api key", err)
+	}
+
+	apiKey := model.ApiKey{
+		UserID:    user.ID,
+		Name:      strings.TrimSpace(request.Name),
+		Prefix:    apikey.DisplayPrefix(key),
+		KeyHash:   apikey.Hash(key),
+		ExpiresAt: request.ExpiresAt,
+	}
+	apiKey.SetScopes(scopes)
+
+	if err := s.apiKeyRepository.Create(ctx, &apiKey); err != nil {
+		logger.Log.Errorw("failed to create api key", "user_id", user.ID, "error", err)
+		return dto.CreatedApiKey{}, errs.NewAppError(500, "failed to create api key", err)
+	}
+
+	logger.Log.Infow("api key created successfully", "user_id", user.ID, "id", apiKey.ID)
+	return dto.CreatedApiKey{ApiKey: apiKey, Key: key}, nil
+}
+
+// DeleteUserApiKey revokes one of the keys of a user.
+func (s *ApiKeyService) DeleteUserApiKey(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := s.apiKeyRepository.DeleteByIDAndUserID(ctx, id.String(), userID.String()); err != nil {
+		if err == errs.ErrApiKeyNotFound {
+			return err
+		}
+		logger.Log.Errorw("failed to delete api key", "id", id, "error", err)
+		return errs.NewAppError(500, "failed to delete api key", err)
+	}
+
+	logger.Log.Infow("api key deleted successfully", "user_id", userID, "id", id)
+	return nil
+}
+
+// Authenticate returns the key matching key and its user. Unknown and expired keys are rejected.
+// The last used time is only written once per ApiKeyLastUsedInterval to spare the database.
+func (s *ApiKeyService) Authenticate(ctx context.Context, key string) (model.User, model.ApiKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	apiKey, err := s.apiKeyRepository.GetByKeyHash(ctx, apikey.Hash(key))
+	if err != nil {
+		if err == errs.ErrApiKeyNotFound {
+			return model.User{}, model.ApiKey{}, errs.ErrUnauthorized
+		}
+		logger.Log.Errorw("failed to get api key", "error", err)
+		return model.User{}, model.ApiKey{}, errs.NewAppError(500, "failed to validate api key", err)
+	}
+
+	if apiKey.IsExpired() {
+		return model.User{}, model.ApiKey{}, errs.ErrUnauthorized
+	}
+
+	user, err := s.userRepository.GetByID(ctx, apiKey.UserID.String())
+	if err != nil {
+		if err == errs.ErrUserNotFound {
+			return model.User{}, model.ApiKey{}, errs.ErrUnauthorized
+		}
+		logger.Log.Errorw("failed to get api key user", "id", apiKey.ID, "error", err)
+		return model.User{}, model.ApiKey{}, errs.NewAppError(500, "failed to validate api key", err)
+	}
+
+	now := time.Now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= constants.ApiKeyLastUsedInterval {
+		if err := s.apiKeyRepository.UpdateLastUsedAt(ctx, apiKey.ID, now); err != nil {
+			logger.Log.Warnw("failed to update api key last used time", "id", apiKey.ID, "error", err)
+		} else {
+			apiKey.LastUsedAt = &now
+		}
+	}
+
+	return user, apiKey, nil
+}
+
+// normalizeApiKeyScopes sorts the scopes and removes duplicates.
+func normalizeApiKeyScopes(scopes []string) []string {
+	scopes = slices.Clone(scopes)
+	slices.Sort(scopes)
+	return slices.Compact(scopes)
+}
+
+// validateApiKeyScopes keeps keys from granting more than the request that mints them:
+// only admins get the admin scope, and api keys only mint keys with some of their own scopes.
+func validateApiKeyScopes(user model.User, currentKey *model.ApiKey, scopes []string) error {
+	if slices.Contains(scopes, model.ApiKeyScopeAdmin) && user.Role != model.UserRoleAdmin {
+		fieldError := errs.NewFieldError("scopes", "admin scope is only available to admins")
+		return errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+
+	if currentKey == nil {
+		return nil
+	}
+
+	if len(scopes) == 0 {
+		fieldError := errs.NewFieldError("scopes", "scopes are required when using an api key")
+		return errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+	for _, scope := range scopes {
+		if !currentKey.HasScope(scope) {
+			fieldError := errs.NewFieldError("scopes", "scope "+scope+" is not granted to the current api key")
+			return errs.NewValidationError([]errs.FieldError{fieldError})
+		}
+	}
+
+	return nil
+}
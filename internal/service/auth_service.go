@@ -3,31 +3,54 @@ package service
 import (
 	"context"
 	"net/http"
+	"strings"
 	"time"
 
+	"github.com/Alfian57/belajar-golang/internal/config"
+	"github.com/Alfian57/belajar-golang/internal/constants"
 	"github.com/Alfian57/belajar-golang/internal/dto"
 	errs "github.com/Alfian57/belajar-golang/internal/errors"
 	"github.com/Alfian57/belajar-golang/internal/logger"
 	"github.com/Alfian57/belajar-golang/internal/model"
 	"github.com/Alfian57/belajar-golang/internal/repository"
+	"github.com/Alfian57/belajar-golang/internal/utils/hash"
+	"github.com/Alfian57/belajar-golang/internal/utils/ipanon"
 	"github.com/Alfian57/belajar-golang/internal/utils/jwt"
+	"github.com/Alfian57/belajar-golang/internal/utils/text"
+	"github.com/Alfian57/belajar-golang/internal/utils/useragent"
+	"github.com/google/uuid"
 )
 
+// maxDeviceLength matches the size of the sessions.device column.
+const maxDeviceLength = 100
+
 type AuthService struct {
 	userRepository         *repository.UserRepository
 	refreshTokenRepository *repository.RefreshTokenRepository
+	sessionRepository      *repository.SessionRepository
+	visitorConfig          config.VisitorConfig
 }
 
-func NewAuthService(userRepository *repository.UserRepository, refreshTokenRepository *repository.RefreshTokenRepository) *AuthService {
+func NewAuthService(userRepository *repository.UserRepository, refreshTokenRepository *repository.RefreshTokenRepository, sessionRepository *repository.SessionRepository) *AuthService {
+	visitorConfig := config.Cfg.Visitor
+
+	// Never fall back to storing full addresses on a misconfigured mode
+	if !ipanon.IsValidMode(visitorConfig.IpMode) {
+		logger.Log.Warnw("invalid visitor ip mode, falling back to truncate", "mode", visitorConfig.IpMode)
+		visitorConfig.IpMode = ipanon.ModeTruncate
+	}
+
 	return &AuthService{
 		userRepository:         userRepository,
 		refreshTokenRepository: refreshTokenRepository,
+		sessionRepository:      sessionRepository,
+		visitorConfig:          visitorConfig,
 	}
 }
 
 // Login authenticates a user using username and password.
-// It generates access and refresh tokens upon successful authentication.
-func (s *AuthService) Login(ctx context.Context, req dto.LoginRequest) (dto.Credentials, error) {
+// It starts a session for the client and generates its access and refresh tokens upon successful authentication.
+func (s *AuthService) Login(ctx context.Context, req dto.LoginRequest, client dto.SessionClient) (dto.Credentials, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
@@ -49,35 +72,33 @@ func (s *AuthService) Login(ctx context.Context, req dto.LoginRequest) (dto.Cred
 
 	// Chehck banned status
 	if user.IsBanned {
-		return credentials, errs.NewAppError(http.StatusForbidden, "user is banned", nil)
+		return credentials, errs.ErrUserBanned
 	}
 
-	// Create access token
-	accessToken, err := jwt.CreateAccessToken(user)
-	if err != nil {
-		return credentials, errs.NewAppError(http.StatusInternalServerError, "failed to create access token", err)
+	// Start a new session, its refresh tokens form one family
+	now := time.Now()
+	session := model.Session{
+		UserID:     user.ID,
+		Device:     sessionDevice(req.DeviceName, client.UserAgent),
+		UserAgent:  text.Truncate(client.UserAgent, maxUserAgentLength),
+		IpAddress:  s.sessionIpAddress(client.IpAddress),
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(constants.RefreshTokenLifetime),
 	}
-
-	// Create refresh token
-	refreshToken, err := jwt.CreateRefreshToken(user)
-	if err != nil {
-		return credentials, errs.NewAppError(http.StatusInternalServerError, "failed to create refresh token", err)
+	if err := s.sessionRepository.Create(ctx, &session); err != nil {
+		logger.Log.Errorw("failed to create session", "user_id", user.ID, "error", err)
+		return credentials, errs.NewAppError(http.StatusInternalServerError, "failed to create session", err)
 	}
 
-	// Save refresh token to repository
-	rt := &model.RefreshToken{
-		UserID:    user.ID,
-		TokenHash: refreshToken,
-		ExpiresAt: time.Now().Add(7 * 24 * time.Hour).Unix(),
-	}
-	if err := s.refreshTokenRepository.Create(ctx, rt); err != nil {
-		logger.Log.Info("error bwang", err)
-		return credentials, errs.NewAppError(http.StatusInternalServerError, "failed to save refresh token", err)
+	credentials, err = s.issueTokens(ctx, user, session.ID)
+	if err != nil {
+		// Don't leave a session behind that can't be refreshed
+		if err := s.sessionRepository.Delete(ctx, session.ID.String()); err != nil {
+			logger.Log.Warnw("failed to delete session without refresh token", "id", session.ID, "error", err)
+		}
+		return credentials, err
 	}
 
-	credentials.AccessToken = accessToken
-	credentials.RefreshToken = refreshToken
-
 	return credentials, nil
 }
 
@@ -134,15 +155,17 @@ func (s *AuthService) Register(ctx context.Context, request dto.RegisterRequest)
 }
 
 // Refresh generates new access and refresh tokens using a valid refresh token.
-// It deletes the old refresh token and saves the new one.
-func (s *AuthService) Refresh(ctx context.Context, refreshTokenParam string) (dto.Credentials, error) {
+// The old refresh token is marked as rotated and the new one joins its family. Presenting a token
+// that was already rotated means it was stolen or replayed, so its whole family is revoked.
+// The session of the family is extended and records the client that refreshed it.
+func (s *AuthService) Refresh(ctx context.Context, refreshTokenParam string, client dto.SessionClient) (dto.Credentials, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
 	credentials := dto.Credentials{}
 
 	// Get refresh token from repository
-	refreshToken, err := s.refreshTokenRepository.GetByTokenHash(ctx, refreshTokenParam)
+	refreshToken, err := s.refreshTokenRepository.GetByTokenHash(ctx, hash.SHA256(refreshTokenParam))
 	if err != nil {
 		if err == errs.ErrRefreshTokenNotFound {
 			return credentials, errs.NewAppError(http.StatusUnauthorized, "refresh token not valid", err)
@@ -150,53 +173,131 @@ func (s *AuthService) Refresh(ctx context.Context, refreshTokenParam string) (dt
 		return credentials, errs.NewAppError(http.StatusInternalServerError, "failed to get refresh token", err)
 	}
 
+	if refreshToken.RotatedAt != nil {
+		return credentials, s.revokeReusedFamily(ctx, refreshToken)
+	}
+
+	if refreshToken.IsExpired() {
+		return credentials, errs.NewAppError(http.StatusUnauthorized, "refresh token not valid", nil)
+	}
+
 	// Get user by ID from refresh token
 	user, err := s.userRepository.GetByID(ctx, refreshToken.UserID.String())
 	if err != nil {
 		return credentials, errs.NewAppError(http.StatusInternalServerError, "failed to get user", err)
 	}
 
-	// Delete old refresh token
-	err = s.refreshTokenRepository.DeleteByTokenHash(ctx, refreshTokenParam)
+	// Sessions are revoked on ban, one that slipped through is revoked now
+	if user.IsBanned {
+		if err := s.sessionRepository.Delete(ctx, refreshToken.FamilyID.String()); err != nil && err != errs.ErrSessionNotFound {
+			logger.Log.Errorw("failed to revoke session of banned user", "user_id", user.ID, "error", err)
+		}
+		return credentials, errs.ErrUserBanned
+	}
+
+	// Rotate the old refresh token, a concurrent refresh with the same token counts as reuse
+	rotated, err := s.refreshTokenRepository.MarkRotated(ctx, refreshToken.ID)
 	if err != nil {
-		return credentials, errs.NewAppError(http.StatusInternalServerError, "failed to delete refresh token", err)
+		return credentials, errs.NewAppError(http.StatusInternalServerError, "failed to rotate refresh token", err)
+	}
+	if !rotated {
+		return credentials, s.revokeReusedFamily(ctx, refreshToken)
 	}
 
-	// Create new access token
-	newAccessToken, err := jwt.CreateAccessToken(user)
+	now := time.Now()
+	session := model.Session{
+		ID:         refreshToken.FamilyID,
+		UserAgent:  text.Truncate(client.UserAgent, maxUserAgentLength),
+		IpAddress:  s.sessionIpAddress(client.IpAddress),
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(constants.RefreshTokenLifetime),
+	}
+	if err := s.sessionRepository.UpdateUsage(ctx, &session); err != nil {
+		return credentials, errs.NewAppError(http.StatusInternalServerError, "failed to update session", err)
+	}
+
+	return s.issueTokens(ctx, user, refreshToken.FamilyID)
+}
+
+// Logout logs out a user by revoking the session of the provided refresh token.
+func (s *AuthService) Logout(ctx context.Context, refreshTokenParam string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	refreshToken, err := s.refreshTokenRepository.GetByTokenHash(ctx, hash.SHA256(refreshTokenParam))
+	if err != nil {
+		return err
+	}
+
+	// Delete the session together with its refresh tokens
+	return s.sessionRepository.Delete(ctx, refreshToken.FamilyID.String())
+}
+
+// issueTokens creates an access token and a refresh token of the given session,
+// whose ID is the family of the refresh token. Only the hash of the refresh token is stored.
+func (s *AuthService) issueTokens(ctx context.Context, user model.User, familyID uuid.UUID) (dto.Credentials, error) {
+	credentials := dto.Credentials{}
+
+	// Create access token
+	accessToken, err := jwt.CreateAccessToken(user, familyID)
 	if err != nil {
 		return credentials, errs.NewAppError(http.StatusInternalServerError, "failed to create access token", err)
 	}
 
-	// Create new refresh token
-	newRefreshToken, err := jwt.CreateRefreshToken(user)
+	// Create refresh token
+	refreshToken, err := jwt.CreateRefreshToken(user, familyID)
 	if err != nil {
 		return credentials, errs.NewAppError(http.StatusInternalServerError, "failed to create refresh token", err)
 	}
 
-	// Save new refresh token to repository
+	// Save refresh token hash to repository
 	rt := &model.RefreshToken{
 		UserID:    user.ID,
-		TokenHash: newRefreshToken,
-		ExpiresAt: time.Now().Add(7 * 24 * time.Hour).Unix(),
+		FamilyID:  familyID,
+		TokenHash: hash.SHA256(refreshToken),
+		ExpiresAt: time.Now().Add(constants.RefreshTokenLifetime).Unix(),
 	}
 	if err := s.refreshTokenRepository.Create(ctx, rt); err != nil {
+		logger.Log.Errorw("failed to save refresh token", "user_id", user.ID, "error", err)
 		return credentials, errs.NewAppError(http.StatusInternalServerError, "failed to save refresh token", err)
 	}
 
-	credentials.AccessToken = newAccessToken
-	credentials.RefreshToken = newRefreshToken
+	credentials.AccessToken = accessToken
+	credentials.RefreshToken = refreshToken
 
 	return credentials, nil
 }
 
-// Logout logs out a user by invalidating the provided refresh token.
-func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
+// revokeReusedFamily revokes the session, and with it every token of the family, of a reused refresh token.
+func (s *AuthService) revokeReusedFamily(ctx context.Context, refreshToken model.RefreshToken) error {
+	logger.Log.Warnw("refresh token reuse detected, revoking its family", "user_id", refreshToken.UserID, "family_id", refreshToken.FamilyID)
+
+	err := s.sessionRepository.Delete(ctx, refreshToken.FamilyID.String())
+	if err != nil && err != errs.ErrSessionNotFound {
+		logger.Log.Errorw("failed to revoke refresh token family", "family_id", refreshToken.FamilyID, "error", err)
+		return errs.NewAppError(http.StatusInternalServerError, "failed to revoke refresh token", err)
+	}
+
+	return errs.NewAppError(http.StatusUnauthorized, "refresh token not valid", nil)
+}
 
-	// Delete the refresh token from the repository
-	err := s.refreshTokenRepository.DeleteByTokenHash(ctx, refreshToken)
+// sessionIpAddress anonymises the address of a session client like the addresses of visitors.
+// Sessions keep no address in hash mode, since the hash doesn't help to recognise a session.
+func (s *AuthService) sessionIpAddress(ip string) *string {
+	address, _ := ipanon.Anonymize(ip, s.visitorConfig.IpMode, s.visitorConfig.IpHashSecret)
+	return text.NullableString(address)
+}
 
-	return err
+// sessionDevice names the device of a session after the name sent by the client,
+// or after the browser and operating system of its user agent.
+func sessionDevice(deviceName string, userAgent string) string {
+	if name := strings.TrimSpace(deviceName); name != "" {
+		return text.Truncate(name, maxDeviceLength)
+	}
+
+	info := useragent.Parse(userAgent)
+	if info.BrowserFamily == useragent.Other && info.OSFamily == useragent.Other {
+		return "Unknown device"
+	}
+	return text.Truncate(info.BrowserFamily+" on "+info.OSFamily, maxDeviceLength)
 }
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/constants"
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/Alfian57/belajar-golang/internal/repository"
+	"github.com/Alfian57/belajar-golang/internal/utils/domain"
+	"github.com/google/uuid"
+)
+
+// runningScans tracks the scans running in the background, so that commands
+// can wait for them before exiting.
+var runningScans sync.WaitGroup
+
+// WaitForBannedDomainScans blocks until every scan started by this process has finished.
+func WaitForBannedDomainScans() {
+	runningScans.Wait()
+}
+
+type BannedDomainScanService struct {
+	bannedDomainScanRepository *repository.BannedDomainScanRepository
+	bannedDomainRepository     *repository.BannedDomainRepository
+	urlRepository              *repository.UrlRepository
+}
+
+func NewBannedDomainScanService(bannedDomainScanRepository *repository.BannedDomainScanRepository, bannedDomainRepository *repository.BannedDomainRepository, urlRepository *repository.UrlRepository) *BannedDomainScanService {
+	return &BannedDomainScanService{
+		bannedDomainScanRepository: bannedDomainScanRepository,
+		bannedDomainRepository:     bannedDomainRepository,
+		urlRepository:              urlRepository,
+	}
+}
+
+// GetAllScans retrieves scan reports with optional status filtering and pagination.
+// It returns a paginated result containing the newest scans first.
+func (s *BannedDomainScanService) GetAllScans(ctx context.Context, query dto.GetBannedDomainScansFilter) (dto.PaginatedResult[model.BannedDomainScan], error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	limit := query.PaginationRequest.Limit
+	offset := query.PaginationRequest.GetOffset()
+
+	scans, err := s.bannedDomainScanRepository.GetAllWithFilterPagination(ctx, query.Status, limit, offset)
+	if err != nil {
+		logger.Log.Errorw("failed to retrieve banned domain scans", "error", err)
+		return dto.PaginatedResult[model.BannedDomainScan]{}, errs.NewAppError(500, "failed to retrieve banned domain scans", err)
+	}
+
+	// Count total scans for pagination
+	count, err := s.bannedDomainScanRepository.CountByStatus(ctx, query.Status)
+	if err != nil {
+		logger.Log.Errorw("failed to count banned domain scans", "error", err)
+		return dto.PaginatedResult[model.BannedDomainScan]{}, errs.NewAppError(500, "failed to retrieve banned domain scans", err)
+	}
+
+	// Create pagination response
+	pagination := dto.NewPaginationResponse(query.Page, query.Limit, count)
+	result := dto.PaginatedResult[model.BannedDomainScan]{
+		Data:       scans,
+		Pagination: pagination,
+	}
+
+	return result, nil
+}
+
+// GetScanByID retrieves a scan report by its ID.
+func (s *BannedDomainScanService) GetScanByID(ctx context.Context, id string) (model.BannedDomainScan, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	scan, err := s.bannedDomainScanRepository.GetByID(ctx, id)
+	if err != nil {
+		if err == errs.ErrBannedDomainScanNotFound {
+			return model.BannedDomainScan{}, err
+		}
+		logger.Log.Errorw("failed to get banned domain scan by ID", "id", id, "error", err)
+		return model.BannedDomainScan{}, errs.NewAppError(500, "failed to retrieve banned domain scan", err)
+	}
+	return scan, nil
+}
+
+// ScanBannedDomain starts a background scan of all urls against a single banned domain rule.
+// It returns the scan report as soon as the scan has been started.
+func (s *BannedDomainScanService) ScanBannedDomain(ctx context.Context, id uuid.UUID) (model.BannedDomainScan, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	bannedDomain, err := s.bannedDomainRepository.GetByID(ctx, id.String())
+	if err != nil {
+		if err == errs.ErrBannedDomainNotFound {
+			return model.BannedDomainScan{}, err
+		}
+		logger.Log.Errorw("failed to get banned domain for scan", "id", id, "error", err)
+		return model.BannedDomainScan{}, errs.NewAppError(500, "failed to retrieve banned domain", err)
+	}
+
+	return s.startScan(ctx, &bannedDomain.ID, []model.BannedDomain{bannedDomain}, false)
+}
+
+// RescanBannedDomain starts a background scan after a rule was updated. The urls the rule had
+// blocked are checked again first and unblocked unless a rule still matches them, then all urls
+// are scanned against the updated rule.
+func (s *BannedDomainScanService) RescanBannedDomain(ctx context.Context, id uuid.UUID) (model.BannedDomainScan, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	bannedDomain, err := s.bannedDomainRepository.GetByID(ctx, id.String())
+	if err != nil {
+		if err == errs.ErrBannedDomainNotFound {
+			return model.BannedDomainScan{}, err
+		}
+		logger.Log.Errorw("failed to get banned domain for scan", "id", id, "error", err)
+		return model.BannedDomainScan{}, errs.NewAppError(500, "failed to retrieve banned domain", err)
+	}
+
+	return s.startScan(ctx, &bannedDomain.ID, []model.BannedDomain{bannedDomain}, true)
+}
+
+// RecheckUnattributedUrls starts a background scan after a rule was deleted. Deleting a rule
+// clears the rule of the urls it blocked, these urls are unblocked unless another rule matches them.
+func (s *BannedDomainScanService) RecheckUnattributedUrls(ctx context.Context) (model.BannedDomainScan, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return s.startScan(ctx, nil, nil, true)
+}
+
+// ScanBannedDomains starts a single background scan of all urls against several rules,
+// which is used after a blocklist import instead of one scan per imported rule.
+func (s *BannedDomainScanService) ScanBannedDomains(ctx context.Context, bannedDomains []model.BannedDomain) (model.BannedDomainScan, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return s.startScan(ctx, nil, bannedDomains, false)
+}
+
+// startScan records a new scan and runs it in the background. With recheck the urls blocked
+// by bannedDomainID, or by no rule when it is nil, are checked against all rules first.
+func (s *BannedDomainScanService) startScan(ctx context.Context, bannedDomainID *uuid.UUID, bannedDomains []model.BannedDomain, recheck bool) (model.BannedDomainScan, error) {
+	scan := model.BannedDomainScan{
+		BannedDomainID: bannedDomainID,
+		RuleCount:      len(bannedDomains),
+		Status:         model.BannedDomainScanStatusRunning,
+		StartedAt:      time.Now(),
+	}
+
+	if err := s.bannedDomainScanRepository.Create(ctx, &scan); err != nil {
+		logger.Log.Errorw("failed to create banned domain scan", "error", err)
+		return model.BannedDomainScan{}, errs.NewAppError(500, "failed to start banned domain scan", err)
+	}
+
+	logger.Log.Infow("banned domain scan started", "scan_id", scan.ID, "rules", scan.RuleCount)
+
+	// The scan outlives the request that started it
+	runningScans.Add(1)
+	go func() {
+		defer runningScans.Done()
+		s.runScan(scan, domain.NewMatcher(bannedDomains), recheck)
+	}()
+
+	return scan, nil
+}
+
+// runScan rechecks the blocked urls when asked to, then walks the urls table in batches,
+// blocks every url matched by the rules and stores the progress and outcome on the scan report.
+func (s *BannedDomainScanService) runScan(scan model.BannedDomainScan, matcher *domain.Matcher, recheck bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), constants.BannedDomainScanTimeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Log.Errorw("banned domain scan panicked", "scan_id", scan.ID, "error", r)
+			s.finishScan(ctx, &scan, model.BannedDomainScanStatusFailed, "scan stopped unexpectedly")
+		}
+	}()
+
+	if recheck {
+		if err := s.recheckBlockedUrls(ctx, &scan); err != nil {
+			logger.Log.Errorw("banned domain scan failed to recheck blocked urls", "scan_id", scan.ID, "error", err)
+			s.finishScan(ctx, &scan, model.BannedDomainScanStatusFailed, err.Error())
+			return
+		}
+	}
+
+	// A recheck after a rule was deleted has no rules left to scan for
+	if scan.RuleCount == 0 {
+		s.finishScan(ctx, &scan, model.BannedDomainScanStatusCompleted, "")
+		return
+	}
+
+	afterID := uuid.Nil
+	for {
+		urls, err := s.urlRepository.GetBatchAfterID(ctx, afterID, constants.BannedDomainScanBatchSize)
+		if err != nil {
+			logger.Log.Errorw("banned domain scan failed to retrieve urls", "scan_id", scan.ID, "error", err)
+			s.finishScan(ctx, &scan, model.BannedDomainScanStatusFailed, err.Error())
+			return
+		}
+		if len(urls) == 0 {
+			break
+		}
+		afterID = urls[len(urls)-1].ID
+
+		// Group the matched urls by the rule that matched them
+		matched := make(map[uuid.UUID][]uuid.UUID)
+		for _, url := range urls {
+			rule, banned, err := matcher.MatchString(url.LongUrl)
+			if err != nil || !banned {
+				continue
+			}
+			matched[rule.ID] = append(matched[rule.ID], url.ID)
+			scan.MatchedCount++
+		}
+
+		for bannedDomainID, urlIDs := range matched {
+			blocked, err := s.urlRepository.BlockByIDs(ctx, urlIDs, bannedDomainID)
+			if err != nil {
+				logger.Log.Errorw("banned domain scan failed to block urls", "scan_id", scan.ID, "error", err)
+				s.finishScan(ctx, &scan, model.BannedDomainScanStatusFailed, err.Error())
+				return
+			}
+			scan.BlockedCount += int(blocked)
+		}
+
+		scan.ScannedCount += len(urls)
+		if err := s.bannedDomainScanRepository.Update(ctx, &scan); err != nil {
+			logger.Log.Warnw("failed to update banned domain scan progress", "scan_id", scan.ID, "error", err)
+		}
+	}
+
+	s.finishScan(ctx, &scan, model.BannedDomainScanStatusCompleted, "")
+}
+
+// recheckBlockedUrls checks the urls blocked by the rule of the scan, or by no rule when the
+// scan has none, against all current rules. Urls that a rule still matches stay blocked by that
+// rule and the others are unblocked.
+func (s *BannedDomainScanService) recheckBlockedUrls(ctx context.Context, scan *model.BannedDomainScan) error {
+	bannedDomains, err := s.bannedDomainRepository.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	matcher := domain.NewMatcher(bannedDomains)
+
+	afterID := uuid.Nil
+	for {
+		urls, err := s.urlRepository.GetBlockedBatchAfterID(ctx, scan.BannedDomainID, afterID, constants.BannedDomainScanBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(urls) == 0 {
+			return nil
+		}
+		afterID = urls[len(urls)-1].ID
+
+		// Group the urls by the rule that still matches them
+		matched := make(map[uuid.UUID][]uuid.UUID)
+		var unmatched []uuid.UUID
+		for _, url := range urls {
+			rule, banned, err := matcher.MatchString(url.LongUrl)
+			if err != nil || !banned {
+				unmatched = append(unmatched, url.ID)
+				continue
+			}
+			if scan.BannedDomainID == nil || rule.ID != *scan.BannedDomainID {
+				matched[rule.ID] = append(matched[rule.ID], url.ID)
+			}
+		}
+
+		for bannedDomainID, urlIDs := range matched {
+			if _, err := s.urlRepository.ReassignBlockByIDs(ctx, urlIDs, bannedDomainID); err != nil {
+				return err
+			}
+		}
+
+		if len(unmatched) > 0 {
+			unblocked, err := s.urlRepository.UnblockByIDs(ctx, unmatched)
+			if err != nil {
+				return err
+			}
+			scan.UnblockedCount += int(unblocked)
+		}
+
+		if err := s.bannedDomainScanRepository.Update(ctx, scan); err != nil {
+			logger.Log.Warnw("failed to update banned domain scan progress", "scan_id", scan.ID, "error", err)
+		}
+	}
+}
+
+func (s *BannedDomainScanService) finishScan(ctx context.Context, scan *model.BannedDomainScan, status string, message string) {
+	finishedAt := time.Now()
+	scan.Status = status
+	scan.Error = message
+	scan.FinishedAt = &finishedAt
+
+	// Record the outcome even when the scan itself ran out of time
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+
+	if err := s.bannedDomainScanRepository.Update(ctx, scan); err != nil {
+		logger.Log.Errorw("failed to store banned domain scan result", "scan_id", scan.ID, "error", err)
+		return
+	}
+
+	logger.Log.Infow("banned domain scan finished", "scan_id", scan.ID, "status", status, "scanned", scan.ScannedCount, "matched", scan.MatchedCount, "blocked", scan.BlockedCount, "unblocked", scan.UnblockedCount)
+}
@@ -2,23 +2,31 @@ package service
 
 import (
 	"context"
+	"errors"
+	"io"
+	"strings"
 	"time"
 
+	"github.com/Alfian57/belajar-golang/internal/constants"
 	"github.com/Alfian57/belajar-golang/internal/dto"
 	errs "github.com/Alfian57/belajar-golang/internal/errors"
 	"github.com/Alfian57/belajar-golang/internal/logger"
 	"github.com/Alfian57/belajar-golang/internal/model"
 	"github.com/Alfian57/belajar-golang/internal/repository"
+	"github.com/Alfian57/belajar-golang/internal/utils/blocklist"
+	"github.com/Alfian57/belajar-golang/internal/utils/domain"
 	"github.com/google/uuid"
 )
 
 type BannedDomainService struct {
-	bannedDomainRepository *repository.BannedDomainRepository
+	bannedDomainRepository  *repository.BannedDomainRepository
+	bannedDomainScanService *BannedDomainScanService
 }
 
-func NewBannedDomainService(repository *repository.BannedDomainRepository) *BannedDomainService {
+func NewBannedDomainService(repository *repository.BannedDomainRepository, bannedDomainScanService *BannedDomainScanService) *BannedDomainService {
 	return &BannedDomainService{
-		bannedDomainRepository: repository,
+		bannedDomainRepository:  repository,
+		bannedDomainScanService: bannedDomainScanService,
 	}
 }
 
@@ -72,7 +80,12 @@ func (s *BannedDomainService) CreateBannedDomain(ctx context.Context, request dt
 	defer cancel()
 
 	bannedDomain := model.BannedDomain{
-		URL: request.Url,
+		URL:      request.Url,
+		RuleType: defaultRuleType(request.RuleType),
+	}
+
+	if err := validateBannedDomainRule(bannedDomain); err != nil {
+		return err
 	}
 
 	// Create the banned domain
@@ -80,8 +93,15 @@ func (s *BannedDomainService) CreateBannedDomain(ctx context.Context, request dt
 		logger.Log.Errorw("failed to create banned domain", "url", request.Url, "error", err)
 		return errs.NewAppError(500, "failed to create banned domain", err)
 	}
+	invalidateBannedDomainMatcher()
 
 	logger.Log.Infow("banned domain created successfully", "url", request.Url)
+
+	// Block existing links that point at the newly banned domain
+	if _, err := s.bannedDomainScanService.ScanBannedDomain(ctx, bannedDomain.ID); err != nil {
+		logger.Log.Errorw("failed to start scan for created banned domain", "id", bannedDomain.ID, "error", err)
+	}
+
 	return nil
 }
 
@@ -104,8 +124,13 @@ func (s *BannedDomainService) UpdateBannedDomain(ctx context.Context, request dt
 
 	// Prepare banned domain data for update
 	bannedDomain := model.BannedDomain{
-		ID:  request.ID,
-		URL: request.Url,
+		ID:       request.ID,
+		URL:      request.Url,
+		RuleType: defaultRuleType(request.RuleType),
+	}
+
+	if err := validateBannedDomainRule(bannedDomain); err != nil {
+		return err
 	}
 
 	// Update the banned domain
@@ -113,8 +138,15 @@ func (s *BannedDomainService) UpdateBannedDomain(ctx context.Context, request dt
 		logger.Log.Errorw("failed to update banned domain", "id", request.ID, "error", err)
 		return errs.NewAppError(500, "failed to update banned domain", err)
 	}
+	invalidateBannedDomainMatcher()
 
 	logger.Log.Infow("banned domain updated successfully", "id", request.ID)
+
+	// Unblock the links that the updated rule no longer matches and block the new matches
+	if _, err := s.bannedDomainScanService.RescanBannedDomain(ctx, request.ID); err != nil {
+		logger.Log.Errorw("failed to start scan for updated banned domain", "id", request.ID, "error", err)
+	}
+
 	return nil
 }
 
@@ -132,7 +164,125 @@ func (s *BannedDomainService) DeleteBannedDomain(ctx context.Context, id uuid.UU
 		logger.Log.Errorw("failed to delete banned domain", "id", id, "error", err)
 		return errs.NewAppError(500, "failed to delete banned domain", err)
 	}
+	invalidateBannedDomainMatcher()
 
 	logger.Log.Infow("banned domain deleted successfully", "id", id)
+
+	// Deleting the rule cleared blocked_by_id of its links, unblock those no other rule matches
+	if _, err := s.bannedDomainScanService.RecheckUnattributedUrls(ctx); err != nil {
+		logger.Log.Errorw("failed to start recheck for deleted banned domain", "id", id, "error", err)
+	}
+
+	return nil
+}
+
+// ImportBannedDomains reads a blocklist and creates a banned domain for every new entry.
+// Entries that already exist are skipped and entries that are not valid rules are counted as invalid.
+func (s *BannedDomainService) ImportBannedDomains(ctx context.Context, reader io.Reader, format string) (dto.ImportBannedDomainsResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+	defer cancel()
+
+	result := dto.ImportBannedDomainsResult{}
+
+	parsed, err := blocklist.Parse(reader, format)
+	if err != nil {
+		if errors.Is(err, blocklist.ErrUnknownFormat) {
+			fieldError := errs.NewFieldError("format", "format is invalid")
+			return result, errs.NewValidationError([]errs.FieldError{fieldError})
+		}
+		logger.Log.Errorw("failed to read blocklist", "error", err)
+		return result, errs.NewAppError(400, "failed to read blocklist", err)
+	}
+	result.Invalid = len(parsed.Invalid)
+
+	// Collect the rules that already exist so that they are not inserted twice
+	existing, err := s.bannedDomainRepository.GetAll(ctx)
+	if err != nil {
+		logger.Log.Errorw("failed to retrieve banned domains", "error", err)
+		return result, errs.NewAppError(500, "failed to import banned domains", err)
+	}
+	seen := make(map[string]bool, len(existing)+len(parsed.Entries))
+	for _, bannedDomain := range existing {
+		seen[bannedDomainKey(bannedDomain.RuleType, bannedDomain.URL)] = true
+	}
+
+	var bannedDomains []model.BannedDomain
+	for _, entry := range parsed.Entries {
+		bannedDomain := model.BannedDomain{
+			URL:      normalizeBannedDomainPattern(entry.RuleType, entry.Url),
+			RuleType: defaultRuleType(entry.RuleType),
+		}
+
+		if len(bannedDomain.URL) > constants.MaxBannedDomainLength || domain.ValidateRule(bannedDomain.RuleType, bannedDomain.URL) != nil {
+			result.Invalid++
+			continue
+		}
+
+		key := bannedDomainKey(bannedDomain.RuleType, bannedDomain.URL)
+		if seen[key] {
+			result.Skipped++
+			continue
+		}
+		seen[key] = true
+
+		bannedDomains = append(bannedDomains, bannedDomain)
+	}
+
+	if len(bannedDomains) > 0 {
+		if err := s.bannedDomainRepository.CreateInBatches(ctx, bannedDomains, constants.BannedDomainImportBatchSize); err != nil {
+			logger.Log.Errorw("failed to import banned domains", "count", len(bannedDomains), "error", err)
+			return result, errs.NewAppError(500, "failed to import banned domains", err)
+		}
+		invalidateBannedDomainMatcher()
+
+		// Block existing links that match any of the imported rules in a single pass
+		if _, err := s.bannedDomainScanService.ScanBannedDomains(ctx, bannedDomains); err != nil {
+			logger.Log.Errorw("failed to start scan for imported banned domains", "count", len(bannedDomains), "error", err)
+		}
+	}
+	result.Added = len(bannedDomains)
+
+	logger.Log.Infow("banned domains imported successfully", "added", result.Added, "skipped", result.Skipped, "invalid", result.Invalid)
+	return result, nil
+}
+
+// normalizeBannedDomainPattern stores host based rules as their normalised host
+// so that the same domain written differently is recognised as a duplicate.
+func normalizeBannedDomainPattern(ruleType string, pattern string) string {
+	pattern = strings.TrimSpace(pattern)
+
+	switch defaultRuleType(ruleType) {
+	case model.BannedDomainRuleExact:
+		if host, err := domain.Host(pattern); err == nil {
+			return host
+		}
+	case model.BannedDomainRuleWildcard:
+		if host, err := domain.Host(strings.TrimPrefix(pattern, "*.")); err == nil {
+			return "*." + host
+		}
+	}
+
+	return pattern
+}
+
+func bannedDomainKey(ruleType string, pattern string) string {
+	ruleType = defaultRuleType(ruleType)
+	return ruleType + "|" + normalizeBannedDomainPattern(ruleType, pattern)
+}
+
+func defaultRuleType(ruleType string) string {
+	if ruleType == "" {
+		return model.BannedDomainRuleExact
+	}
+	return ruleType
+}
+
+// validateBannedDomainRule checks that the rule pattern can be compiled for its rule type.
+func validateBannedDomainRule(bannedDomain model.BannedDomain) error {
+	if err := domain.ValidateRule(bannedDomain.RuleType, bannedDomain.URL); err != nil {
+		logger.Log.Infow("invalid banned domain rule", "url", bannedDomain.URL, "rule_type", bannedDomain.RuleType, "error", err)
+		fieldError := errs.NewFieldError("url", "url is not a valid "+bannedDomain.RuleType+" rule")
+		return errs.NewValidationError([]errs.FieldError{fieldError})
+	}
 	return nil
 }
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/repository"
+	"github.com/Alfian57/belajar-golang/internal/utils/useragent"
+)
+
+// botPatternCheckInterval is how often the cached matcher asks the database
+// whether the bot_patterns table changed in another process.
+const botPatternCheckInterval = 30 * time.Second
+
+// botMatcherCache is shared by every BotDetectionService so that the
+// patterns are loaded once per process instead of once per injected service.
+type botMatcherCache struct {
+	mu          sync.RWMutex
+	matcher     *useragent.Matcher
+	stale       bool
+	count       int64
+	lastUpdated time.Time
+	checkedAt   time.Time
+}
+
+var botMatcher = &botMatcherCache{}
+
+// invalidateBotMatcher forces the next check to reload the patterns.
+// The current matcher is kept in case the reload fails.
+func invalidateBotMatcher() {
+	botMatcher.mu.Lock()
+	defer botMatcher.mu.Unlock()
+
+	botMatcher.stale = true
+}
+
+type BotDetectionService struct {
+	botPatternRepository *repository.BotPatternRepository
+}
+
+func NewBotDetectionService(botPatternRepository *repository.BotPatternRepository) *BotDetectionService {
+	return &BotDetectionService{
+		botPatternRepository: botPatternRepository,
+	}
+}
+
+// IsBot reports whether a visit looks automated. Besides the known crawlers and the
+// admin defined User-Agent patterns, HEAD requests and requests without a User-Agent
+// or Accept header are treated as bots, since browsers always send both.
+// When the patterns can't be refreshed the last loaded ones are used, and when they
+// were never loaded only the other checks are applied.
+func (s *BotDetectionService) IsBot(ctx context.Context, request dto.RecordVisitRequest) bool {
+	if request.Method == http.MethodHead {
+		return true
+	}
+	if strings.TrimSpace(request.UserAgent) == "" || strings.TrimSpace(request.Accept) == "" {
+		return true
+	}
+	if useragent.Parse(request.UserAgent).IsBot {
+		return true
+	}
+
+	matcher, err := s.getMatcher(ctx)
+	if err != nil {
+		return false
+	}
+
+	return matcher.Match(request.UserAgent)
+}
+
+// getMatcher returns the cached matcher, rebuilding it when it was invalidated
+// or when the table version changed since it was loaded. When the patterns can't be
+// loaded the previous matcher is returned until the next check, if there is one.
+func (s *BotDetectionService) getMatcher(ctx context.Context) (*useragent.Matcher, error) {
+	cache := botMatcher
+
+	cache.mu.RLock()
+	matcher, stale, checkedAt := cache.matcher, cache.stale, cache.checkedAt
+	cache.mu.RUnlock()
+
+	if matcher != nil && !stale && time.Since(checkedAt) < botPatternCheckInterval {
+		return matcher, nil
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	// Another request may have refreshed the cache while we waited for the lock
+	if cache.matcher != nil && !cache.stale && time.Since(cache.checkedAt) < botPatternCheckInterval {
+		return cache.matcher, nil
+	}
+
+	count, lastUpdated, err := s.botPatternRepository.GetVersion(ctx)
+	if err != nil {
+		logger.Log.Errorw("failed to check bot patterns version", "error", err)
+		return cache.fallback(err)
+	}
+
+	if cache.matcher != nil && !cache.stale && count == cache.count && lastUpdated.Equal(cache.lastUpdated) {
+		cache.checkedAt = time.Now()
+		return cache.matcher, nil
+	}
+
+	botPatterns, err := s.botPatternRepository.GetAll(ctx)
+	if err != nil {
+		logger.Log.Errorw("failed to retrieve bot patterns", "error", err)
+		return cache.fallback(err)
+	}
+
+	patterns := make([]string, 0, len(botPatterns))
+	for _, botPattern := range botPatterns {
+		patterns = append(patterns, botPattern.Pattern)
+	}
+
+	cache.matcher = useragent.NewMatcher(patterns)
+	cache.stale = false
+	cache.count = count
+	cache.lastUpdated = lastUpdated
+	cache.checkedAt = time.Now()
+
+	logger.Log.Infow("bot patterns loaded", "count", count)
+	return cache.matcher, nil
+}
+
+// fallback keeps using the last loaded matcher after a failed refresh and waits for the next
+// check before trying again, which still reloads an invalidated matcher since the change
+// that invalidated it also changed the table version. It returns err when no matcher was loaded yet.
+// The caller must hold the write lock.
+func (c *botMatcherCache) fallback(err error) (*useragent.Matcher, error) {
+	if c.matcher == nil {
+		return nil, err
+	}
+
+	logger.Log.Warnw("using the previously loaded bot patterns", "error", err)
+	c.stale = false
+	c.checkedAt = time.Now()
+	return c.matcher, nil
+}
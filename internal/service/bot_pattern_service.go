@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/Alfian57/belajar-golang/internal/repository"
+	"github.com/google/uuid"
+)
+
+type BotPatternService struct {
+	botPatternRepository *repository.BotPatternRepository
+}
+
+func NewBotPatternService(botPatternRepository *repository.BotPatternRepository) *BotPatternService {
+	return &BotPatternService{
+		botPatternRepository: botPatternRepository,
+	}
+}
+
+// GetAllBotPatterns retrieves all bot patterns with optional filtering and pagination.
+// It returns a paginated result containing bot pattern data.
+func (s *BotPatternService) GetAllBotPatterns(ctx context.Context, query dto.GetBotPatternsFilter) (dto.PaginatedResult[model.BotPattern], error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// Set default ordering
+	orderBy := query.OrderBy
+	if orderBy == "" {
+		orderBy = "created_at"
+	}
+	orderType := query.OrderType
+	if orderType != "ASC" && orderType != "DESC" {
+		orderType = "ASC"
+	}
+
+	// Validate pagination parameters (SetDefaults should be called before this)
+	limit := query.PaginationRequest.Limit
+	offset := query.PaginationRequest.GetOffset()
+
+	botPatterns, err := s.botPatternRepository.GetAllWithFilterPagination(ctx, strings.ToLower(query.Search), orderBy, orderType, limit, offset)
+	if err != nil {
+		logger.Log.Errorw("failed to retrieve bot patterns", "error", err)
+		return dto.PaginatedResult[model.BotPattern]{}, errs.NewAppError(500, "failed to retrieve bot patterns", err)
+	}
+
+	// Count total bot patterns for pagination
+	count, err := s.botPatternRepository.CountByPattern(ctx, strings.ToLower(query.Search))
+	if err != nil {
+		logger.Log.Errorw("failed to count bot patterns", "error", err)
+		return dto.PaginatedResult[model.BotPattern]{}, errs.NewAppError(500, "failed to retrieve bot patterns", err)
+	}
+
+	// Create pagination response
+	pagination := dto.NewPaginationResponse(query.Page, query.Limit, count)
+	result := dto.PaginatedResult[model.BotPattern]{
+		Data:       botPatterns,
+		Pagination: pagination,
+	}
+
+	return result, nil
+}
+
+// CreateBotPattern creates a new bot pattern with the provided request data.
+// Patterns are stored in lower case, as they are matched case-insensitively.
+func (s *BotPatternService) CreateBotPattern(ctx context.Context, request dto.CreateBotPatternRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	botPattern := model.BotPattern{
+		Pattern:     normalizeBotPattern(request.Pattern),
+		Description: strings.TrimSpace(request.Description),
+	}
+
+	if err := validateBotPattern(botPattern.Pattern); err != nil {
+		return err
+	}
+
+	// Create the bot pattern
+	if err := s.botPatternRepository.Create(ctx, &botPattern); err != nil {
+		if err == errs.ErrBotPatternExist {
+			return err
+		}
+		logger.Log.Errorw("failed to create bot pattern", "pattern", botPattern.Pattern, "error", err)
+		return errs.NewAppError(500, "failed to create bot pattern", err)
+	}
+	invalidateBotMatcher()
+
+	logger.Log.Infow("bot pattern created successfully", "pattern", botPattern.Pattern)
+	return nil
+}
+
+// UpdateBotPattern updates an existing bot pattern with the provided request data.
+// It returns an error if the bot pattern does not exist or if the update fails.
+func (s *BotPatternService) UpdateBotPattern(ctx context.Context, request dto.UpdateBotPatternRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// Check if the bot pattern exists
+	_, err := s.botPatternRepository.GetByID(ctx, request.ID.String())
+	if err != nil {
+		if err == errs.ErrBotPatternNotFound {
+			return err
+		}
+
+		logger.Log.Errorw("failed to check bot pattern existence for update", "id", request.ID, "error", err)
+		return errs.NewAppError(500, "failed to validate bot pattern", err)
+	}
+
+	// Prepare bot pattern data for update
+	botPattern := model.BotPattern{
+		ID:          request.ID,
+		Pattern:     normalizeBotPattern(request.Pattern),
+		Description: strings.TrimSpace(request.Description),
+	}
+
+	if err := validateBotPattern(botPattern.Pattern); err != nil {
+		return err
+	}
+
+	// Update the bot pattern
+	if err := s.botPatternRepository.Update(ctx, &botPattern); err != nil {
+		if err == errs.ErrBotPatternExist {
+			return err
+		}
+		logger.Log.Errorw("failed to update bot pattern", "id", request.ID, "error", err)
+		return errs.NewAppError(500, "failed to update bot pattern", err)
+	}
+	invalidateBotMatcher()
+
+	logger.Log.Infow("bot pattern updated successfully", "id", request.ID)
+	return nil
+}
+
+// DeleteBotPattern deletes a bot pattern by its ID.
+// It returns an error if the bot pattern does not exist or if the deletion fails.
+func (s *BotPatternService) DeleteBotPattern(ctx context.Context, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := s.botPatternRepository.Delete(ctx, id.String()); err != nil {
+		if err == errs.ErrBotPatternNotFound {
+			return err
+		}
+		logger.Log.Errorw("failed to delete bot pattern", "id", id, "error", err)
+		return errs.NewAppError(500, "failed to delete bot pattern", err)
+	}
+	invalidateBotMatcher()
+
+	logger.Log.Infow("bot pattern deleted successfully", "id", id)
+	return nil
+}
+
+func normalizeBotPattern(pattern string) string {
+	return strings.ToLower(strings.TrimSpace(pattern))
+}
+
+// validateBotPattern rejects patterns that are too short once trimmed, since they would match most browsers.
+func validateBotPattern(pattern string) error {
+	if len(pattern) < 2 {
+		fieldError := errs.NewFieldError("pattern", "pattern must be at least 2 characters")
+		return errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/Alfian57/belajar-golang/internal/repository"
+	"github.com/Alfian57/belajar-golang/internal/utils/domain"
+)
+
+// bannedDomainCheckInterval is how often the cached matcher asks the database
+// whether the banned_domains table changed in another process.
+const bannedDomainCheckInterval = 30 * time.Second
+
+// bannedDomainMatcherCache is shared by every DomainPolicyService so that the
+// rules are compiled once per process instead of once per injected service.
+type bannedDomainMatcherCache struct {
+	mu          sync.RWMutex
+	matcher     *domain.Matcher
+	count       int64
+	lastUpdated time.Time
+	checkedAt   time.Time
+}
+
+var bannedDomainMatcher = &bannedDomainMatcherCache{}
+
+// invalidateBannedDomainMatcher forces the next check to reload the rules.
+func invalidateBannedDomainMatcher() {
+	bannedDomainMatcher.mu.Lock()
+	defer bannedDomainMatcher.mu.Unlock()
+
+	bannedDomainMatcher.matcher = nil
+}
+
+type DomainPolicyService struct {
+	bannedDomainRepository *repository.BannedDomainRepository
+}
+
+func NewDomainPolicyService(bannedDomainRepository *repository.BannedDomainRepository) *DomainPolicyService {
+	return &DomainPolicyService{
+		bannedDomainRepository: bannedDomainRepository,
+	}
+}
+
+// IsBanned reports whether rawUrl matches any banned domain rule.
+// It returns domain.ErrInvalidUrl when rawUrl has no usable host.
+func (s *DomainPolicyService) IsBanned(ctx context.Context, rawUrl string) (bool, error) {
+	_, banned, err := s.Match(ctx, rawUrl)
+	return banned, err
+}
+
+// Match returns the banned domain rule that rawUrl matches, if any.
+// It returns domain.ErrInvalidUrl when rawUrl has no usable host.
+func (s *DomainPolicyService) Match(ctx context.Context, rawUrl string) (model.BannedDomain, bool, error) {
+	u, err := domain.Parse(rawUrl)
+	if err != nil {
+		return model.BannedDomain{}, false, err
+	}
+
+	matcher, err := s.getMatcher(ctx)
+	if err != nil {
+		return model.BannedDomain{}, false, err
+	}
+
+	rule, banned := matcher.Match(u)
+	return rule, banned, nil
+}
+
+// getMatcher returns the cached matcher, rebuilding it when it was invalidated
+// or when the table version changed since it was loaded.
+func (s *DomainPolicyService) getMatcher(ctx context.Context) (*domain.Matcher, error) {
+	cache := bannedDomainMatcher
+
+	cache.mu.RLock()
+	matcher, checkedAt := cache.matcher, cache.checkedAt
+	cache.mu.RUnlock()
+
+	if matcher != nil && time.Since(checkedAt) < bannedDomainCheckInterval {
+		return matcher, nil
+	}
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	// Another request may have refreshed the cache while we waited for the lock
+	if cache.matcher != nil && time.Since(cache.checkedAt) < bannedDomainCheckInterval {
+		return cache.matcher, nil
+	}
+
+	count, lastUpdated, err := s.bannedDomainRepository.GetVersion(ctx)
+	if err != nil {
+		logger.Log.Errorw("failed to check banned domains version", "error", err)
+		return nil, err
+	}
+
+	if cache.matcher != nil && count == cache.count && lastUpdated.Equal(cache.lastUpdated) {
+		cache.checkedAt = time.Now()
+		return cache.matcher, nil
+	}
+
+	bannedDomains, err := s.bannedDomainRepository.GetAll(ctx)
+	if err != nil {
+		logger.Log.Errorw("failed to retrieve banned domains", "error", err)
+		return nil, err
+	}
+
+	matcher = domain.NewMatcher(bannedDomains)
+	for _, invalid := range matcher.Invalid() {
+		logger.Log.Warnw("skipping invalid banned domain rule", "id", invalid.ID, "url", invalid.URL, "rule_type", invalid.RuleType)
+	}
+
+	cache.matcher = matcher
+	cache.count = count
+	cache.lastUpdated = lastUpdated
+	cache.checkedAt = time.Now()
+
+	logger.Log.Infow("banned domain rules loaded", "count", count)
+	return matcher, nil
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/model"
+	"github.com/Alfian57/belajar-golang/internal/repository"
+	"github.com/google/uuid"
+)
+
+type SessionService struct {
+	sessionRepository *repository.SessionRepository
+	userRepository    *repository.UserRepository
+}
+
+func NewSessionService(sessionRepository *repository.SessionRepository, userRepository *repository.UserRepository) *SessionService {
+	return &SessionService{
+		sessionRepository: sessionRepository,
+		userRepository:    userRepository,
+	}
+}
+
+// GetUserSessions retrieves the active sessions of a user, most recently used first.
+// The session with currentSessionID is flagged as the current one.
+func (s *SessionService) GetUserSessions(ctx context.Context, userID uuid.UUID, currentSessionID string) ([]model.Session, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := s.checkUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.sessionRepository.GetActiveByUserID(ctx, userID.String())
+	if err != nil {
+		logger.Log.Errorw("failed to retrieve sessions", "user_id", userID, "error", err)
+		return nil, errs.NewAppError(500, "failed to retrieve sessions", err)
+	}
+
+	for i := range sessions {
+		sessions[i].IsCurrent = sessions[i].ID.String() == currentSessionID
+	}
+
+	return sessions, nil
+}
+
+// RevokeUserSession revokes one of the sessions of a user together with its refresh tokens.
+func (s *SessionService) RevokeUserSession(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := s.sessionRepository.DeleteByIDAndUserID(ctx, id.String(), userID.String()); err != nil {
+		if err == errs.ErrSessionNotFound {
+			return err
+		}
+		logger.Log.Errorw("failed to revoke session", "id", id, "error", err)
+		return errs.NewAppError(500, "failed to revoke session", err)
+	}
+
+	logger.Log.Infow("session revoked successfully", "user_id", userID, "id", id)
+	return nil
+}
+
+// RevokeUserSessions logs a user out everywhere by revoking all of their sessions.
+func (s *SessionService) RevokeUserSessions(ctx context.Context, userID uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := s.checkUserExists(ctx, userID); err != nil {
+		return err
+	}
+
+	count, err := s.sessionRepository.DeleteByUserID(ctx, userID.String())
+	if err != nil {
+		logger.Log.Errorw("failed to revoke sessions", "user_id", userID, "error", err)
+		return errs.NewAppError(500, "failed to revoke sessions", err)
+	}
+
+	logger.Log.Infow("sessions revoked successfully", "user_id", userID, "count", count)
+	return nil
+}
+
+// ValidateSession checks that the session of an access token still exists, so that
+// revoking a session also rejects its access tokens before they expire.
+func (s *SessionService) ValidateSession(ctx context.Context, userID string, sessionID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	session, err := s.sessionRepository.GetByID(ctx, sessionID)
+	if err != nil {
+		if err == errs.ErrSessionNotFound {
+			return errs.ErrUnauthorized
+		}
+		logger.Log.Errorw("failed to get session", "id", sessionID, "error", err)
+		return errs.NewAppError(500, "failed to validate session", err)
+	}
+
+	if session.UserID.String() != userID || session.IsExpired() {
+		return errs.ErrUnauthorized
+	}
+
+	return nil
+}
+
+func (s *SessionService) checkUserExists(ctx context.Context, userID uuid.UUID) error {
+	if _, err := s.userRepository.GetByID(ctx, userID.String()); err != nil {
+		if err == errs.ErrUserNotFound {
+			return err
+		}
+		logger.Log.Errorw("failed to check user existence", "user_id", userID, "error", err)
+		return errs.NewAppError(500, "failed to validate user", err)
+	}
+	return nil
+}
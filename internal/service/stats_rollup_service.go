@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/Alfian57/belajar-golang/internal/dto"
+	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/repository"
+)
+
+// StatsRollupService rolls the visits of past UTC days up into the daily stats tables.
+// Today is never rolled up, as its visits are still coming in.
+type StatsRollupService struct {
+	statsRollupRepository *repository.StatsRollupRepository
+}
+
+func NewStatsRollupService(statsRollupRepository *repository.StatsRollupRepository) *StatsRollupService {
+	return &StatsRollupService{
+		statsRollupRepository: statsRollupRepository,
+	}
+}
+
+// Rollup rolls up every day after the last rolled up day until yesterday.
+// The first run starts at the day of the oldest visit.
+func (s *StatsRollupService) Rollup(ctx context.Context) (dto.StatsRollupResult, error) {
+	from, ok, err := s.statsRollupRepository.GetRolledUpUntil(ctx)
+	if err != nil {
+		logger.Log.Errorw("failed to retrieve stats rollup progress", "error", err)
+		return dto.StatsRollupResult{}, errs.NewAppError(500, "failed to retrieve stats rollup progress", err)
+	}
+
+	if !ok {
+		from, ok, err = s.statsRollupRepository.GetFirstVisitDay(ctx)
+		if err != nil {
+			logger.Log.Errorw("failed to retrieve first visit day", "error", err)
+			return dto.StatsRollupResult{}, errs.NewAppError(500, "failed to retrieve first visit day", err)
+		}
+
+		// Nothing to roll up before the first visit
+		if !ok {
+			return dto.StatsRollupResult{}, nil
+		}
+	}
+
+	return s.rollupDays(ctx, from, today())
+}
+
+// Backfill rolls up the UTC days from from to to, both included, replacing their existing rollups.
+// It refuses ranges that include today or later.
+func (s *StatsRollupService) Backfill(ctx context.Context, from time.Time, to time.Time) (dto.StatsRollupResult, error) {
+	from = truncateToInterval(from.UTC(), "day")
+	to = truncateToInterval(to.UTC(), "day")
+
+	if to.Before(from) {
+		fieldError := errs.NewFieldError("from", "from must not be after to")
+		return dto.StatsRollupResult{}, errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+	if !to.Before(today()) {
+		fieldError := errs.NewFieldError("to", "to must be before today")
+		return dto.StatsRollupResult{}, errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+
+	return s.rollupDays(ctx, from, to.AddDate(0, 0, 1))
+}
+
+// rollupDays rolls up the days from from until, but not including, until.
+// Every day is committed on its own, so an interrupted run keeps the days it finished.
+func (s *StatsRollupService) rollupDays(ctx context.Context, from time.Time, until time.Time) (dto.StatsRollupResult, error) {
+	var result dto.StatsRollupResult
+	for day := from; day.Before(until); day = day.AddDate(0, 0, 1) {
+		visits, err := s.statsRollupRepository.RollupDay(ctx, day)
+		if err != nil {
+			logger.Log.Errorw("failed to roll up stats", "day", day.Format(time.DateOnly), "error", err)
+			return result, errs.NewAppError(500, "failed to roll up stats", err)
+		}
+
+		result.Days++
+		result.Visits += visits
+	}
+
+	return result, nil
+}
+
+// today returns the start of the current UTC day.
+func today() time.Time {
+	return truncateToInterval(time.Now().UTC(), "day")
+}
@@ -2,25 +2,59 @@ package service
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"strings"
 	"time"
 
+	"github.com/Alfian57/belajar-golang/internal/config"
 	"github.com/Alfian57/belajar-golang/internal/dto"
 	errs "github.com/Alfian57/belajar-golang/internal/errors"
 	"github.com/Alfian57/belajar-golang/internal/logger"
 	"github.com/Alfian57/belajar-golang/internal/model"
 	"github.com/Alfian57/belajar-golang/internal/repository"
+	"github.com/Alfian57/belajar-golang/internal/utils/domain"
+	"github.com/Alfian57/belajar-golang/internal/utils/ratelimit"
+	"github.com/Alfian57/belajar-golang/internal/utils/shortcode"
+	"github.com/Alfian57/belajar-golang/internal/utils/signedtoken"
 	"github.com/google/uuid"
 )
 
 type UrlService struct {
-	urlRepository  *repository.UrlRepository
-	userRepository *repository.UserRepository
+	urlRepository       *repository.UrlRepository
+	userRepository      *repository.UserRepository
+	domainPolicyService *DomainPolicyService
+	urlConfig           config.UrlConfig
+	passwordLimiter     *ratelimit.Limiter
 }
 
-func NewUrlService(urlRepository *repository.UrlRepository, userRepository *repository.UserRepository) *UrlService {
+// Generated short urls must fit the bounds of custom short urls and the urls.short_url column.
+const (
+	minShortUrlLength         = 3
+	maxShortUrlLength         = 100
+	defaultShortUrlLength     = 7
+	defaultShortUrlMaxRetries = 5
+)
+
+func NewUrlService(urlRepository *repository.UrlRepository, userRepository *repository.UserRepository, domainPolicyService *DomainPolicyService) *UrlService {
+	urlConfig := config.Cfg.Url
+
+	// Fall back to the defaults rather than generating empty codes or never trying at all
+	if urlConfig.ShortUrlLength < minShortUrlLength || urlConfig.ShortUrlLength > maxShortUrlLength {
+		logger.Log.Warnw("invalid short url length, falling back to the default", "length", urlConfig.ShortUrlLength, "default", defaultShortUrlLength)
+		urlConfig.ShortUrlLength = defaultShortUrlLength
+	}
+	if urlConfig.ShortUrlMaxRetries < 1 {
+		logger.Log.Warnw("invalid short url max retries, falling back to the default", "max_retries", urlConfig.ShortUrlMaxRetries, "default", defaultShortUrlMaxRetries)
+		urlConfig.ShortUrlMaxRetries = defaultShortUrlMaxRetries
+	}
+
 	return &UrlService{
-		urlRepository:  urlRepository,
-		userRepository: userRepository,
+		urlRepository:       urlRepository,
+		userRepository:      userRepository,
+		domainPolicyService: domainPolicyService,
+		urlConfig:           urlConfig,
+		passwordLimiter:     ratelimit.New(config.Cfg.Url.PasswordMaxAttempts, config.Cfg.Url.PasswordAttemptWindow),
 	}
 }
 
@@ -44,14 +78,14 @@ func (s *UrlService) GetAllUrls(ctx context.Context, query dto.GetUrlsFilter) (d
 	limit := query.PaginationRequest.Limit
 	offset := query.PaginationRequest.GetOffset()
 
-	urls, err := s.urlRepository.GetAllWithFilterPagination(ctx, query.Search, orderBy, orderType, limit, offset)
+	urls, err := s.urlRepository.GetAllWithFilterPagination(ctx, query.UserID, query.Search, orderBy, orderType, limit, offset)
 	if err != nil {
 		logger.Log.Errorw("failed to retrieve urls", "error", err)
 		return dto.PaginatedResult[model.Url]{}, errs.NewAppError(500, "failed to retrieve urls", err)
 	}
 
 	// Count total urls for pagination
-	count, err := s.urlRepository.CountByShortUrl(ctx, query.Search)
+	count, err := s.urlRepository.CountByShortUrl(ctx, query.UserID, query.Search)
 	if err != nil {
 		logger.Log.Errorw("failed to count urls", "error", err)
 		return dto.PaginatedResult[model.Url]{}, errs.NewAppError(500, "failed to retrieve urls", err)
@@ -89,23 +123,141 @@ func (s *UrlService) CreateUrl(ctx context.Context, request dto.CreateUrlRequest
 		return errs.NewAppError(400, "invalid userID format", err)
 	}
 
+	if err := s.validateLongUrl(ctx, request.LongUrl); err != nil {
+		return err
+	}
+
+	if err := validateActiveWindow(request.ActiveFrom, request.ExpiredAt); err != nil {
+		return err
+	}
+
 	url := model.Url{
-		ShortUrl:  request.ShortUrl,
-		LongUrl:   request.LongUrl,
-		UserID:    userID,
-		ExpiredAt: request.ExpiredAt,
+		ShortUrl:   request.ShortUrl,
+		LongUrl:    request.LongUrl,
+		UserID:     userID,
+		ActiveFrom: request.ActiveFrom,
+		ExpiredAt:  request.ExpiredAt,
+		MaxClicks:  request.MaxClicks,
 	}
 
-	// Create the url
-	if err := s.urlRepository.Create(ctx, &url); err != nil {
-		logger.Log.Errorw("failed to create url", "short_url", request.ShortUrl, "error", err)
-		return errs.NewAppError(500, "failed to create url", err)
+	// Protect the url with a password when given
+	if request.Password != "" {
+		if err := url.SetHashedPassword(request.Password); err != nil {
+			logger.Log.Errorw("failed to hash url password", "error", err)
+			return errs.NewAppError(500, "failed to create url", err)
+		}
 	}
 
-	logger.Log.Infow("url created successfully", "short_url", request.ShortUrl)
+	// Use the requested short url when given, otherwise generate one
+	if request.ShortUrl != "" {
+		if err := s.validateShortUrl(request.ShortUrl); err != nil {
+			return err
+		}
+
+		if err := s.urlRepository.Create(ctx, &url); err != nil {
+			if err == errs.ErrShortUrlExist {
+				fieldError := errs.NewFieldError("short_url", "short url already exists")
+				return errs.NewValidationError([]errs.FieldError{fieldError})
+			}
+			logger.Log.Errorw("failed to create url", "short_url", request.ShortUrl, "error", err)
+			return errs.NewAppError(500, "failed to create url", err)
+		}
+	} else {
+		if err := s.createWithGeneratedShortUrl(ctx, &url); err != nil {
+			return err
+		}
+	}
+
+	logger.Log.Infow("url created successfully", "short_url", url.ShortUrl)
 	return nil
 }
 
+// createWithGeneratedShortUrl creates the url with a random short url.
+// It retries with a new code when the generated one collides with an existing url.
+func (s *UrlService) createWithGeneratedShortUrl(ctx context.Context, url *model.Url) error {
+	for attempt := 0; attempt < s.urlConfig.ShortUrlMaxRetries; attempt++ {
+		code, err := shortcode.Generate(s.urlConfig.ShortUrlLength)
+		if err != nil {
+			logger.Log.Errorw("failed to generate short url", "error", err)
+			return errs.NewAppError(500, "failed to generate short url", err)
+		}
+
+		if s.isReservedShortUrl(code) {
+			continue
+		}
+
+		url.ShortUrl = code
+		err = s.urlRepository.Create(ctx, url)
+		if err == nil {
+			return nil
+		}
+		if err != errs.ErrShortUrlExist {
+			logger.Log.Errorw("failed to create url", "short_url", code, "error", err)
+			return errs.NewAppError(500, "failed to create url", err)
+		}
+
+		logger.Log.Infow("generated short url already exists, retrying", "short_url", code, "attempt", attempt+1)
+	}
+
+	logger.Log.Errorw("failed to generate a unique short url", "attempts", s.urlConfig.ShortUrlMaxRetries)
+	return errs.NewAppError(500, "failed to generate a unique short url", nil)
+}
+
+// validateShortUrl checks that a user supplied short url is well formed and not reserved.
+func (s *UrlService) validateShortUrl(shortUrl string) error {
+	if !shortcode.IsValid(shortUrl) {
+		fieldError := errs.NewFieldError("short_url", "short url may only contain letters, numbers, dashes and underscores")
+		return errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+
+	if s.isReservedShortUrl(shortUrl) {
+		fieldError := errs.NewFieldError("short_url", "short url is reserved")
+		return errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+
+	return nil
+}
+
+// validateLongUrl checks that the long url has a valid host that is not banned.
+func (s *UrlService) validateLongUrl(ctx context.Context, longUrl string) error {
+	banned, err := s.domainPolicyService.IsBanned(ctx, longUrl)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidUrl) {
+			fieldError := errs.NewFieldError("long_url", "long url is invalid")
+			return errs.NewValidationError([]errs.FieldError{fieldError})
+		}
+		logger.Log.Errorw("failed to check banned domains", "long_url", longUrl, "error", err)
+		return errs.NewAppError(500, "failed to validate long url", err)
+	}
+
+	if banned {
+		logger.Log.Infow("long url domain is banned", "long_url", longUrl)
+		fieldError := errs.NewFieldError("long_url", "long url domain is banned")
+		return errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+
+	return nil
+}
+
+// validateActiveWindow checks that a url becomes active before it expires.
+func validateActiveWindow(activeFrom *time.Time, expiredAt time.Time) error {
+	if activeFrom != nil && !activeFrom.Before(expiredAt) {
+		fieldError := errs.NewFieldError("active_from", "active from must be before expired at")
+		return errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+
+	return nil
+}
+
+func (s *UrlService) isReservedShortUrl(shortUrl string) bool {
+	for _, reserved := range s.urlConfig.ReservedShortUrls {
+		if strings.EqualFold(strings.TrimSpace(reserved), shortUrl) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetUrlByID retrieves a url by its ID.
 // It returns the url data or an error if the url does not exist.
 func (s *UrlService) GetUrlByID(ctx context.Context, id string) (model.Url, error) {
@@ -124,6 +276,140 @@ func (s *UrlService) GetUrlByID(ctx context.Context, id string) (model.Url, erro
 	return url, nil
 }
 
+// GetUserUrlByID retrieves a url by its ID if it belongs to the given user.
+// It returns ErrUrlNotFound for urls owned by someone else.
+func (s *UrlService) GetUserUrlByID(ctx context.Context, userID uuid.UUID, id string) (model.Url, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	url, err := s.urlRepository.GetByIDAndUserID(ctx, id, userID.String())
+	if err != nil {
+		if err == errs.ErrUrlNotFound {
+			return model.Url{}, err
+		}
+		logger.Log.Errorw("failed to get user url by ID", "id", id, "user_id", userID, "error", err)
+		return model.Url{}, errs.NewAppError(500, "failed to retrieve url", err)
+	}
+	return url, nil
+}
+
+// ResolveShortUrl retrieves the url behind a short code so that it can be redirected to.
+// It returns an error if the url does not exist, has already expired or points at a banned domain.
+func (s *UrlService) ResolveShortUrl(ctx context.Context, shortUrl string) (model.Url, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	url, err := s.urlRepository.GetByShortUrl(ctx, shortUrl)
+	if err != nil {
+		if err == errs.ErrUrlNotFound {
+			return model.Url{}, err
+		}
+		logger.Log.Errorw("failed to get url by short url", "short_url", shortUrl, "error", err)
+		return model.Url{}, errs.NewAppError(500, "failed to retrieve url", err)
+	}
+
+	// Reject links that are outside of their active window
+	now := time.Now()
+	if url.ActiveFrom != nil && now.Before(*url.ActiveFrom) {
+		return model.Url{}, errs.ErrUrlNotActive
+	}
+	if now.After(url.ExpiredAt) {
+		return model.Url{}, errs.ErrUrlExpired
+	}
+
+	// Reject links that were already visited as often as they allow
+	if url.IsClickLimitReached() {
+		return model.Url{}, errs.ErrUrlClickLimit
+	}
+
+	// Reject links that were blocked by a banned domain scan
+	if url.IsBlocked {
+		return model.Url{}, errs.ErrUrlBlocked
+	}
+
+	// Reject links whose domain was banned after they were created. A host that can't be
+	// normalised can't match a rule either, so it doesn't stop a stored link from redirecting.
+	banned, err := s.domainPolicyService.IsBanned(ctx, url.LongUrl)
+	if err != nil && !errors.Is(err, domain.ErrInvalidUrl) {
+		logger.Log.Errorw("failed to check banned domains", "short_url", shortUrl, "error", err)
+		return model.Url{}, errs.NewAppError(500, "failed to retrieve url", err)
+	}
+	if err != nil {
+		logger.Log.Warnw("failed to normalise host of url, skipping banned domain check", "short_url", shortUrl, "error", err)
+	}
+	if banned {
+		logger.Log.Infow("refusing to redirect to blocked url", "short_url", shortUrl, "long_url", url.LongUrl)
+		return model.Url{}, errs.ErrUrlBlocked
+	}
+
+	return url, nil
+}
+
+// RegisterClick counts a visit of the url right before redirecting to it.
+// It returns ErrUrlClickLimit when concurrent visits used up the last allowed click first.
+func (s *UrlService) RegisterClick(ctx context.Context, url model.Url) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	counted, err := s.urlRepository.IncrementClickCount(ctx, url.ID)
+	if err != nil {
+		logger.Log.Errorw("failed to increment click count", "short_url", url.ShortUrl, "error", err)
+		return errs.NewAppError(500, "failed to register click", err)
+	}
+	if !counted {
+		return errs.ErrUrlClickLimit
+	}
+
+	return nil
+}
+
+// UnlockUrl resolves a password protected short code and checks the submitted password.
+// Wrong attempts are counted per client and url, and refused with ErrTooManyAttempts once the limit is reached.
+func (s *UrlService) UnlockUrl(ctx context.Context, shortUrl string, password string, clientKey string) (model.Url, error) {
+	url, err := s.ResolveShortUrl(ctx, shortUrl)
+	if err != nil {
+		return model.Url{}, err
+	}
+
+	// Urls without a password are always unlocked
+	if !url.HasPassword {
+		return url, nil
+	}
+
+	limiterKey := url.ID.String() + "|" + clientKey
+	if !s.passwordLimiter.Allow(limiterKey) {
+		logger.Log.Infow("too many url password attempts", "short_url", shortUrl, "client", clientKey)
+		return model.Url{}, errs.ErrTooManyAttempts
+	}
+
+	if err := url.CheckHashedPassword(password); err != nil {
+		s.passwordLimiter.Hit(limiterKey)
+		return model.Url{}, errs.ErrUrlPasswordIncorrect
+	}
+
+	s.passwordLimiter.Reset(limiterKey)
+	return url, nil
+}
+
+// CreateUrlAccessToken returns a signed token that lets the holder skip the password prompt of the url.
+// The token is bound to the current password hash, so changing the password invalidates it.
+func (s *UrlService) CreateUrlAccessToken(url model.Url) (string, time.Duration) {
+	token := signedtoken.Create(urlAccessSubject(url), time.Now().Add(s.urlConfig.AccessTTL), s.urlConfig.AccessSecret)
+	return token, s.urlConfig.AccessTTL
+}
+
+// HasUrlAccess reports whether the url can be visited with the given access token.
+func (s *UrlService) HasUrlAccess(url model.Url, token string) bool {
+	if !url.HasPassword {
+		return true
+	}
+	return signedtoken.Verify(token, urlAccessSubject(url), s.urlConfig.AccessSecret)
+}
+
+func urlAccessSubject(url model.Url) string {
+	return url.ID.String() + "|" + url.Password
+}
+
 // UpdateUrl updates an existing url with the provided request data.
 // It returns an error if the url does not exist or if the update fails.
 func (s *UrlService) UpdateUrl(ctx context.Context, request dto.UpdateUrlRequest) error {
@@ -141,17 +427,73 @@ func (s *UrlService) UpdateUrl(ctx context.Context, request dto.UpdateUrlRequest
 		return errs.NewAppError(500, "failed to validate url", err)
 	}
 
+	return s.updateUrl(ctx, currentUrl, request)
+}
+
+// UpdateUserUrl updates an existing url if it belongs to the given user.
+// It returns ErrUrlNotFound for urls owned by someone else.
+func (s *UrlService) UpdateUserUrl(ctx context.Context, userID uuid.UUID, request dto.UpdateUrlRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// Check if the url exists and is owned by the user
+	currentUrl, err := s.urlRepository.GetByIDAndUserID(ctx, request.ID.String(), userID.String())
+	if err != nil {
+		if err == errs.ErrUrlNotFound {
+			return err
+		}
+
+		logger.Log.Errorw("failed to check url ownership for update", "id", request.ID, "user_id", userID, "error", err)
+		return errs.NewAppError(500, "failed to validate url", err)
+	}
+
+	return s.updateUrl(ctx, currentUrl, request)
+}
+
+func (s *UrlService) updateUrl(ctx context.Context, currentUrl model.Url, request dto.UpdateUrlRequest) error {
+	if err := s.validateShortUrl(request.ShortUrl); err != nil {
+		return err
+	}
+
+	if err := s.validateLongUrl(ctx, request.LongUrl); err != nil {
+		return err
+	}
+
+	if err := validateActiveWindow(request.ActiveFrom, request.ExpiredAt); err != nil {
+		return err
+	}
+
 	// Prepare url data for update
 	url := model.Url{
-		ID:        request.ID,
-		ShortUrl:  request.ShortUrl,
-		LongUrl:   request.LongUrl,
-		UserID:    currentUrl.UserID, // Keep the original user ID
-		ExpiredAt: request.ExpiredAt,
+		ID:         request.ID,
+		ShortUrl:   request.ShortUrl,
+		LongUrl:    request.LongUrl,
+		UserID:     currentUrl.UserID, // Keep the original user ID
+		ActiveFrom: request.ActiveFrom,
+		ExpiredAt:  request.ExpiredAt,
+		MaxClicks:  request.MaxClicks,
+		Password:   currentUrl.Password, // Keep the original password unless it is changed
+		// The new long url passed the domain policy, so a block of the old one no longer applies
+		IsBlocked:   false,
+		BlockedByID: nil,
+	}
+
+	// Replace or remove the password when requested
+	if request.Password != "" {
+		if err := url.SetHashedPassword(request.Password); err != nil {
+			logger.Log.Errorw("failed to hash url password", "id", request.ID, "error", err)
+			return errs.NewAppError(500, "failed to update url", err)
+		}
+	} else if request.RemovePassword {
+		url.Password = ""
 	}
 
 	// Update the url
 	if err := s.urlRepository.Update(ctx, &url); err != nil {
+		if err == errs.ErrShortUrlExist {
+			fieldError := errs.NewFieldError("short_url", "short url already exists")
+			return errs.NewValidationError([]errs.FieldError{fieldError})
+		}
 		logger.Log.Errorw("failed to update url", "id", request.ID, "error", err)
 		return errs.NewAppError(500, "failed to update url", err)
 	}
@@ -160,6 +502,43 @@ func (s *UrlService) UpdateUrl(ctx context.Context, request dto.UpdateUrlRequest
 	return nil
 }
 
+// UnblockUrl lifts the block of a url that was blocked by a banned domain scan.
+// Urls that still match a banned domain can't be unblocked, they would be refused on redirect anyway.
+func (s *UrlService) UnblockUrl(ctx context.Context, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	url, err := s.urlRepository.GetByID(ctx, id.String())
+	if err != nil {
+		if err == errs.ErrUrlNotFound {
+			return err
+		}
+		logger.Log.Errorw("failed to get url for unblock", "id", id, "error", err)
+		return errs.NewAppError(500, "failed to unblock url", err)
+	}
+
+	if !url.IsBlocked {
+		return errs.NewAppError(http.StatusConflict, "url is not blocked", nil)
+	}
+
+	banned, err := s.domainPolicyService.IsBanned(ctx, url.LongUrl)
+	if err != nil && !errors.Is(err, domain.ErrInvalidUrl) {
+		logger.Log.Errorw("failed to check banned domains for unblock", "id", id, "error", err)
+		return errs.NewAppError(500, "failed to unblock url", err)
+	}
+	if banned {
+		return errs.NewAppError(http.StatusConflict, "url still matches a banned domain", nil)
+	}
+
+	if _, err := s.urlRepository.UnblockByIDs(ctx, []uuid.UUID{id}); err != nil {
+		logger.Log.Errorw("failed to unblock url", "id", id, "error", err)
+		return errs.NewAppError(500, "failed to unblock url", err)
+	}
+
+	logger.Log.Infow("url unblocked successfully", "id", id)
+	return nil
+}
+
 // DeleteUrl deletes a url by their ID.
 // It returns an error if the url does not exist or if the deletion fails.
 func (s *UrlService) DeleteUrl(ctx context.Context, id uuid.UUID) error {
@@ -179,6 +558,24 @@ func (s *UrlService) DeleteUrl(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// DeleteUserUrl deletes a url by its ID if it belongs to the given user.
+// It returns ErrUrlNotFound for urls owned by someone else.
+func (s *UrlService) DeleteUserUrl(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := s.urlRepository.DeleteByIDAndUserID(ctx, id.String(), userID.String()); err != nil {
+		if err == errs.ErrUrlNotFound {
+			return err
+		}
+		logger.Log.Errorw("failed to delete user url", "id", id, "user_id", userID, "error", err)
+		return errs.NewAppError(500, "failed to delete url", err)
+	}
+
+	logger.Log.Infow("url deleted successfully", "id", id, "user_id", userID)
+	return nil
+}
+
 // CountUrls retrieves the total number of URLs in the repository.
 // It returns the count of URLs or an error if the operation fails.
 func (s *UrlService) Count(ctx context.Context) (int64, error) {
@@ -2,45 +2,78 @@ package service
 
 import (
 	"context"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/Alfian57/belajar-golang/internal/constants"
+	"github.com/Alfian57/belajar-golang/internal/dto"
 	errs "github.com/Alfian57/belajar-golang/internal/errors"
+	"github.com/Alfian57/belajar-golang/internal/ingest"
 	"github.com/Alfian57/belajar-golang/internal/logger"
+	"github.com/Alfian57/belajar-golang/internal/model"
 	"github.com/Alfian57/belajar-golang/internal/repository"
+	"github.com/Alfian57/belajar-golang/internal/utils/domain"
+	"github.com/Alfian57/belajar-golang/internal/utils/hll"
+	"github.com/Alfian57/belajar-golang/internal/utils/text"
+	"github.com/Alfian57/belajar-golang/internal/utils/useragent"
+	"github.com/google/uuid"
 )
 
+// timeseriesIntervals maps the supported timeseries intervals to their nominal length
+// and the default range that is returned when no start is given.
+var timeseriesIntervals = map[string]struct {
+	length       time.Duration
+	defaultRange time.Duration
+}{
+	"hour": {time.Hour, 24 * time.Hour},
+	"day":  {24 * time.Hour, 30 * 24 * time.Hour},
+	"week": {7 * 24 * time.Hour, 12 * 7 * 24 * time.Hour},
+}
+
+// bucketKeyLayout formats the wall clock time of a bucket so that database and generated buckets can be matched.
+const bucketKeyLayout = "2006-01-02 15:04:05"
+
+// maxUserAgentLength bounds the stored user agent so that clients can't bloat the table.
+const maxUserAgentLength = 1024
+
+// maxReferrerHostLength matches the size of the url_visitors.referrer_host column.
+const maxReferrerHostLength = 255
+
 type UrlVisitorService struct {
-	urlVisitorRepository *repository.UrlVisitorRepository
-	urlRepository        *repository.UrlRepository
+	urlVisitorRepository    *repository.UrlVisitorRepository
+	urlRepository           *repository.UrlRepository
+	uniqueVisitorRepository *repository.UniqueVisitorRepository
+	statsRollupRepository   *repository.StatsRollupRepository
 }
 
-func NewUrlVisitorService(urlVisitorRepository *repository.UrlVisitorRepository, urlRepository *repository.UrlRepository) *UrlVisitorService {
+func NewUrlVisitorService(urlVisitorRepository *repository.UrlVisitorRepository, urlRepository *repository.UrlRepository, uniqueVisitorRepository *repository.UniqueVisitorRepository, statsRollupRepository *repository.StatsRollupRepository) *UrlVisitorService {
 	return &UrlVisitorService{
-		urlVisitorRepository: urlVisitorRepository,
-		urlRepository:        urlRepository,
+		urlVisitorRepository:    urlVisitorRepository,
+		urlRepository:           urlRepository,
+		uniqueVisitorRepository: uniqueVisitorRepository,
+		statsRollupRepository:   statsRollupRepository,
 	}
 }
 
-// CountUrls retrieves the total number of URLs in the repository.
-// It returns the count of URLs or an error if the operation fails.
-func (s *UrlVisitorService) Count(ctx context.Context) (int64, error) {
+// Count retrieves the total and unique number of URL visitors over a date range.
+// It returns the counts or an error if the operation fails.
+func (s *UrlVisitorService) Count(ctx context.Context, query dto.CountVisitorsFilter) (dto.VisitorCount, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	// Count the total number of url visitors
-	count, err := s.urlVisitorRepository.Count(ctx)
-	if err != nil {
-		logger.Log.Errorw("failed to count url visitors", "error", err)
-		return 0, errs.NewAppError(500, "failed to count url visitors", err)
-	}
-
-	return count, nil
+	return s.count(ctx, "", uuid.Nil, query)
 }
 
-// CountByUrlID retrieves the total number of URL visitors for a specific URL ID.
-// It returns the count of visitors or an error if the operation fails.
-func (s *UrlVisitorService) CountByUrlID(ctx context.Context, urlID string) (int64, error) {
+// CountByUrlID retrieves the total and unique number of URL visitors for a specific URL ID over a date range.
+// It returns the counts or an error if the operation fails.
+func (s *UrlVisitorService) CountByUrlID(ctx context.Context, urlID string, query dto.CountVisitorsFilter) (dto.VisitorCount, error) {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
@@ -49,19 +82,629 @@ func (s *UrlVisitorService) CountByUrlID(ctx context.Context, urlID string) (int
 	if err != nil {
 		if errors.Is(err, errs.ErrUrlNotFound) {
 			logger.Log.Errorw("invalid url id", "url_id", urlID)
-			return 0, errs.NewAppError(400, "invalid url id", nil)
+			return dto.VisitorCount{}, errs.NewAppError(400, "invalid url id", nil)
 		} else {
 			logger.Log.Errorw("failed to validate url id", "error", err)
-			return 0, errs.NewAppError(500, "failed to validate url id", err)
+			return dto.VisitorCount{}, errs.NewAppError(500, "failed to validate url id", err)
 		}
 	}
 
+	return s.count(ctx, urlID, uuid.Nil, query)
+}
+
+// CountUserByUrlID retrieves the total and unique number of visitors of a url if it belongs to the given user.
+// It returns ErrUrlNotFound for urls owned by someone else.
+func (s *UrlVisitorService) CountUserByUrlID(ctx context.Context, userID uuid.UUID, urlID string, query dto.CountVisitorsFilter) (dto.VisitorCount, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := s.checkUrl(ctx, urlID, userID); err != nil {
+		return dto.VisitorCount{}, err
+	}
+
+	return s.count(ctx, urlID, userID, query)
+}
+
+// CountUser retrieves the total and unique number of visitors of all urls owned by the given user.
+func (s *UrlVisitorService) CountUser(ctx context.Context, userID uuid.UUID, query dto.CountVisitorsFilter) (dto.VisitorCount, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return s.count(ctx, "", userID, query)
+}
+
+// count counts the visits and unique visitors of a url, of the urls of a user, or of all urls
+// when neither urlID nor userID is given.
+func (s *UrlVisitorService) count(ctx context.Context, urlID string, userID uuid.UUID, query dto.CountVisitorsFilter) (dto.VisitorCount, error) {
+	from, to, location, err := resolveStatsRange(query.StatsRangeFilter, constants.DefaultStatsRange)
+	if err != nil {
+		return dto.VisitorCount{}, err
+	}
+
+	ownerID := ""
+	if userID != uuid.Nil {
+		ownerID = userID.String()
+	}
+
+	period, err := s.statsPeriod(ctx, from, to, true)
+	if err != nil {
+		return dto.VisitorCount{}, err
+	}
+
 	// Count the total number of url visitors
-	count, err := s.urlVisitorRepository.CountByUrlID(ctx, urlID)
+	count, err := s.urlVisitorRepository.CountInPeriod(ctx, urlID, ownerID, period, query.IncludeBots)
+	if err != nil {
+		logger.Log.Errorw("failed to count url visitors", "url_id", urlID, "user_id", userID, "error", err)
+		return dto.VisitorCount{}, errs.NewAppError(500, "failed to count url visitors", err)
+	}
+
+	unique, err := s.countUnique(ctx, urlID, userID, from, to, query.IncludeBots)
+	if err != nil {
+		return dto.VisitorCount{}, err
+	}
+
+	result := dto.VisitorCount{
+		Total:  count,
+		Unique: unique,
+		From:   from.In(location),
+		To:     to.In(location),
+	}
+
+	return result, nil
+}
+
+// GetBreakdownByUrlID returns the most common referrers, devices, browsers, operating systems
+// or countries of the visitors of a url over a date range.
+// It returns an error if the url does not exist.
+func (s *UrlVisitorService) GetBreakdownByUrlID(ctx context.Context, urlID string, query dto.GetVisitorBreakdownFilter) (dto.VisitorBreakdownResult, error) {
+	return s.getBreakdown(ctx, urlID, uuid.Nil, query)
+}
+
+// GetUserBreakdownByUrlID returns the breakdown of the visitors of a url if it belongs to the given user.
+// It returns ErrUrlNotFound for urls owned by someone else.
+func (s *UrlVisitorService) GetUserBreakdownByUrlID(ctx context.Context, userID uuid.UUID, urlID string, query dto.GetVisitorBreakdownFilter) (dto.VisitorBreakdownResult, error) {
+	return s.getBreakdown(ctx, urlID, userID, query)
+}
+
+// GetUserBreakdown returns the breakdown of the visitors of all urls owned by the given user.
+func (s *UrlVisitorService) GetUserBreakdown(ctx context.Context, userID uuid.UUID, query dto.GetVisitorBreakdownFilter) (dto.VisitorBreakdownResult, error) {
+	return s.getBreakdown(ctx, "", userID, query)
+}
+
+func (s *UrlVisitorService) getBreakdown(ctx context.Context, urlID string, userID uuid.UUID, query dto.GetVisitorBreakdownFilter) (dto.VisitorBreakdownResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	from, to, location, err := resolveStatsRange(query.StatsRangeFilter, constants.DefaultStatsRange)
+	if err != nil {
+		return dto.VisitorBreakdownResult{}, err
+	}
+
+	// Set default size
+	limit := query.Limit
+	if limit == 0 {
+		limit = constants.DefaultBreakdownSize
+	}
+
+	// Check if the url exists, unless the whole account is requested
+	if urlID != "" {
+		if err := s.checkUrl(ctx, urlID, userID); err != nil {
+			return dto.VisitorBreakdownResult{}, err
+		}
+	}
+
+	ownerID := ""
+	if userID != uuid.Nil {
+		ownerID = userID.String()
+	}
+
+	period, err := s.statsPeriod(ctx, from, to, true)
+	if err != nil {
+		return dto.VisitorBreakdownResult{}, err
+	}
+
+	items, err := s.urlVisitorRepository.CountGroupedBy(ctx, urlID, ownerID, query.Dimension, period, query.IncludeBots, limit)
+	if err != nil {
+		logger.Log.Errorw("failed to retrieve url visitor breakdown", "url_id", urlID, "user_id", userID, "dimension", query.Dimension, "error", err)
+		return dto.VisitorBreakdownResult{}, errs.NewAppError(500, "failed to retrieve url visitor breakdown", err)
+	}
+
+	unique, err := s.countUnique(ctx, urlID, userID, from, to, query.IncludeBots)
+	if err != nil {
+		return dto.VisitorBreakdownResult{}, err
+	}
+
+	result := dto.VisitorBreakdownResult{
+		Dimension: query.Dimension,
+		From:      from.In(location),
+		To:        to.In(location),
+		Unique:    unique,
+		Items:     items,
+	}
+
+	return result, nil
+}
+
+// GetTimeseriesByUrlID counts the visits of a url per hour, day or week over a date range.
+// Empty buckets are filled with zeros. It returns an error if the url does not exist.
+func (s *UrlVisitorService) GetTimeseriesByUrlID(ctx context.Context, urlID string, query dto.GetTimeseriesFilter) (dto.TimeseriesResult, error) {
+	return s.getTimeseries(ctx, urlID, uuid.Nil, query)
+}
+
+// GetUserTimeseriesByUrlID counts the visits of a url per hour, day or week if it belongs to the given user.
+// It returns ErrUrlNotFound for urls owned by someone else.
+func (s *UrlVisitorService) GetUserTimeseriesByUrlID(ctx context.Context, userID uuid.UUID, urlID string, query dto.GetTimeseriesFilter) (dto.TimeseriesResult, error) {
+	return s.getTimeseries(ctx, urlID, userID, query)
+}
+
+func (s *UrlVisitorService) getTimeseries(ctx context.Context, urlID string, userID uuid.UUID, query dto.GetTimeseriesFilter) (dto.TimeseriesResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	// Set default interval
+	interval := query.Interval
+	if interval == "" {
+		interval = constants.DefaultStatsInterval
+	}
+	intervalConfig := timeseriesIntervals[interval]
+
+	from, to, location, err := resolveStatsRange(query.StatsRangeFilter, intervalConfig.defaultRange)
+	if err != nil {
+		return dto.TimeseriesResult{}, err
+	}
+
+	// Refuse ranges that would return an unreasonable number of buckets
+	if to.Sub(from)/intervalConfig.length > constants.MaxTimeseriesBuckets {
+		fieldError := errs.NewFieldError("from", "date range is too large for the interval")
+		return dto.TimeseriesResult{}, errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+
+	// Check if the url exists
+	if err := s.checkUrl(ctx, urlID, userID); err != nil {
+		return dto.TimeseriesResult{}, err
+	}
+
+	// The rollups hold UTC days, so they can't be split into hours or days of another timezone
+	period, err := s.statsPeriod(ctx, from, to, interval != "hour" && location == time.UTC)
+	if err != nil {
+		return dto.TimeseriesResult{}, err
+	}
+
+	rows, err := s.urlVisitorRepository.CountByUrlIDPerInterval(ctx, urlID, interval, location.String(), period, query.IncludeBots)
+	if err != nil {
+		logger.Log.Errorw("failed to retrieve url visitor timeseries", "url_id", urlID, "interval", interval, "error", err)
+		return dto.TimeseriesResult{}, errs.NewAppError(500, "failed to retrieve url visitor timeseries", err)
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Bucket.Format(bucketKeyLayout)] = row.Count
+	}
+
+	// Fill the buckets without visits with zeros. A wall clock hour that is repeated
+	// when daylight saving time ends is a single bucket, as it is for date_trunc.
+	points := []dto.TimeseriesPoint{}
+	previousKey := ""
+	for bucket := truncateToInterval(from.In(location), interval); bucket.Before(to); bucket = nextInterval(bucket, interval) {
+		key := bucket.Format(bucketKeyLayout)
+		if key == previousKey {
+			continue
+		}
+		previousKey = key
+
+		points = append(points, dto.TimeseriesPoint{
+			Bucket: bucket,
+			Count:  counts[key],
+		})
+	}
+
+	unique, err := s.countUnique(ctx, urlID, userID, from, to, query.IncludeBots)
+	if err != nil {
+		return dto.TimeseriesResult{}, err
+	}
+
+	result := dto.TimeseriesResult{
+		Interval: interval,
+		Timezone: location.String(),
+		From:     from.In(location),
+		To:       to.In(location),
+		Unique:   unique,
+		Points:   points,
+	}
+
+	return result, nil
+}
+
+// checkUrl returns ErrUrlNotFound unless the url exists and, when userID is set, belongs to that user.
+func (s *UrlVisitorService) checkUrl(ctx context.Context, urlID string, userID uuid.UUID) error {
+	var err error
+	if userID == uuid.Nil {
+		_, err = s.urlRepository.GetByID(ctx, urlID)
+	} else {
+		_, err = s.urlRepository.GetByIDAndUserID(ctx, urlID, userID.String())
+	}
+
+	if err != nil {
+		if errors.Is(err, errs.ErrUrlNotFound) {
+			return err
+		}
+		logger.Log.Errorw("failed to validate url id", "url_id", urlID, "error", err)
+		return errs.NewAppError(500, "failed to validate url id", err)
+	}
+
+	return nil
+}
+
+// statsPeriod reads the full UTC days between from and to that are already rolled up from the
+// daily rollups, and the rest of the range, which always includes today, from the raw visits.
+func (s *UrlVisitorService) statsPeriod(ctx context.Context, from time.Time, to time.Time, useRollup bool) (repository.StatsPeriod, error) {
+	period := repository.RawStatsPeriod(from, to)
+	if !useRollup {
+		return period, nil
+	}
+
+	rolledUpUntil, ok, err := s.statsRollupRepository.GetRolledUpUntil(ctx)
+	if err != nil {
+		logger.Log.Errorw("failed to retrieve stats rollup progress", "error", err)
+		return period, errs.NewAppError(500, "failed to retrieve stats rollup progress", err)
+	}
+	if !ok {
+		return period, nil
+	}
+
+	// Round the start up and the end down to whole UTC days
+	rollupFrom := truncateToInterval(from.UTC(), "day")
+	if rollupFrom.Before(from) {
+		rollupFrom = rollupFrom.AddDate(0, 0, 1)
+	}
+	rollupTo := truncateToInterval(to.UTC(), "day")
+	if rolledUpUntil.Before(rollupTo) {
+		rollupTo = rolledUpUntil
+	}
+
+	if rollupFrom.Before(rollupTo) {
+		period.RollupFrom = rollupFrom
+		period.RollupTo = rollupTo
+	}
+
+	return period, nil
+}
+
+// resolveStatsRange applies the defaults of a stats date range and validates it.
+// The range ends now and spans defaultRange unless given otherwise, in UTC unless a timezone is given.
+func resolveStatsRange(query dto.StatsRangeFilter, defaultRange time.Duration) (time.Time, time.Time, *time.Location, error) {
+	timezone := query.Timezone
+	if timezone == "" {
+		timezone = constants.DefaultStatsTimezone
+	}
+
+	// Only accept IANA names that Postgres knows as well
+	location, err := time.LoadLocation(timezone)
+	if err != nil || timezone == "Local" {
+		fieldError := errs.NewFieldError("timezone", "timezone is invalid")
+		return time.Time{}, time.Time{}, nil, errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+
+	to := time.Now()
+	if query.To != nil {
+		to = *query.To
+	}
+	from := to.Add(-defaultRange)
+	if query.From != nil {
+		from = *query.From
+	}
+
+	if !from.Before(to) {
+		fieldError := errs.NewFieldError("from", "from must be before to")
+		return time.Time{}, time.Time{}, nil, errs.NewValidationError([]errs.FieldError{fieldError})
+	}
+
+	return from, to, location, nil
+}
+
+// truncateToInterval returns the start of the hour, day or week that t falls in, in the location of t.
+// Weeks start on Monday, as they do for Postgres date_trunc.
+func truncateToInterval(t time.Time, interval string) time.Time {
+	switch interval {
+	case "hour":
+		// Subtract rather than rebuild the time, so an hour that is repeated when
+		// daylight saving time ends keeps its own offset
+		return t.Add(-time.Duration(t.Minute())*time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	case "week":
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	}
+}
+
+// nextInterval returns the start of the bucket after t. Hours are added as elapsed time,
+// since the wall clock hour after t may not exist when daylight saving time starts.
+func nextInterval(t time.Time, interval string) time.Time {
+	switch interval {
+	case "hour":
+		return truncateToInterval(t.Add(time.Hour), interval)
+	case "week":
+		return t.AddDate(0, 0, 7)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// RecordVisit queues a single visit of a short url, which is stored in the background in batches.
+// The user agent is parsed into its device, operating system and browser so that
+// breakdowns don't need to re-parse it, and is truncated before being stored.
+// Only the host of the referrer is kept. The location lookup and the anonymisation of the ip address
+// are left to the workers that store the visit.
+func (s *UrlVisitorService) RecordVisit(ctx context.Context, request dto.RecordVisitRequest) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	userAgent := text.Truncate(request.UserAgent, maxUserAgentLength)
+
+	info := useragent.Parse(request.UserAgent)
+
+	// Only the host of the referrer is kept, visits without one are direct visits
+	referrerHost := ""
+	if request.Referrer != "" {
+		referrerHost, _ = domain.Host(request.Referrer)
+	}
+	if len(referrerHost) > maxReferrerHostLength {
+		referrerHost = ""
+	}
+
+	urlVisitor := model.URLVisitor{
+		UrlID:          request.UrlID,
+		UserAgent:      userAgent,
+		DeviceType:     info.DeviceType,
+		OSFamily:       info.OSFamily,
+		OSVersion:      info.OSVersion,
+		BrowserFamily:  info.BrowserFamily,
+		BrowserVersion: info.BrowserVersion,
+		IsBot:          request.IsBot || info.IsBot,
+		ReferrerHost:   referrerHost,
+		CreatedAt:      time.Now(),
+		ClientIP:       request.IpAddress,
+	}
+
+	if err := ingest.Enqueue(ctx, urlVisitor); err != nil {
+		logger.Log.Warnw("failed to enqueue url visit", "url_id", request.UrlID, "error", err)
+		return errs.NewAppError(503, "failed to record url visit", err)
+	}
+
+	return nil
+}
+
+// countUnique estimates the number of unique visitors between from and to by merging the daily sketches
+// of every UTC day that the range touches. Visitors are counted for a url when urlID is given, for the
+// urls of a user when userID is given, and for all urls otherwise. Bot visitors are left out unless
+// includeBots is set.
+func (s *UrlVisitorService) countUnique(ctx context.Context, urlID string, userID uuid.UUID, from time.Time, to time.Time, includeBots bool) (uint64, error) {
+	fromDay := truncateToInterval(from.UTC(), "day")
+	toDay := truncateToInterval(to.UTC(), "day")
+	if toDay.Before(to) {
+		toDay = toDay.AddDate(0, 0, 1)
+	}
+
+	merged := hll.New()
+	merge := func(data []byte) error {
+		sketch, err := hll.FromBytes(data)
+		if err != nil {
+			logger.Log.Warnw("skipping invalid unique visitor sketch", "error", err)
+			return nil
+		}
+		merged.Merge(sketch)
+		return nil
+	}
+
+	var err error
+	if urlID == "" && userID == uuid.Nil {
+		err = s.uniqueVisitorRepository.EachSketch(ctx, fromDay, toDay, includeBots, merge)
+	} else {
+		ownerID := ""
+		if userID != uuid.Nil {
+			ownerID = userID.String()
+		}
+		err = s.uniqueVisitorRepository.EachUrlSketch(ctx, urlID, ownerID, fromDay, toDay, includeBots, merge)
+	}
+	if err != nil {
+		logger.Log.Errorw("failed to retrieve unique visitor sketches", "url_id", urlID, "user_id", userID, "error", err)
+		return 0, errs.NewAppError(500, "failed to count unique visitors", err)
+	}
+
+	return merged.Count(), nil
+}
+
+// visitorExportColumns are the columns of a CSV export, in order.
+var visitorExportColumns = []string{
+	"id", "url_id", "created_at", "ip_address", "ip_hash", "user_agent", "device_type", "os_family", "os_version",
+	"browser_family", "browser_version", "is_bot", "referrer_host", "country", "region", "city",
+}
+
+// VisitorExport is a validated export of visitor rows, written once the response is ready to be streamed.
+type VisitorExport struct {
+	ContentType string
+	Filename    string
+
+	urlID       string
+	userID      string
+	from        time.Time
+	to          time.Time
+	location    *time.Location
+	format      string
+	includeBots bool
+
+	urlVisitorRepository *repository.UrlVisitorRepository
+}
+
+// ExportByUrlID prepares an export of the visitors of a url over a date range.
+// It returns an error if the url does not exist.
+func (s *UrlVisitorService) ExportByUrlID(ctx context.Context, urlID string, query dto.ExportVisitorsFilter) (VisitorExport, error) {
+	return s.prepareExport(ctx, urlID, uuid.Nil, query)
+}
+
+// ExportUserByUrlID prepares an export of the visitors of a url if it belongs to the given user.
+// It returns ErrUrlNotFound for urls owned by someone else.
+func (s *UrlVisitorService) ExportUserByUrlID(ctx context.Context, userID uuid.UUID, urlID string, query dto.ExportVisitorsFilter) (VisitorExport, error) {
+	return s.prepareExport(ctx, urlID, userID, query)
+}
+
+// ExportUser prepares an export of the visitors of all urls owned by the given user.
+func (s *UrlVisitorService) ExportUser(ctx context.Context, userID uuid.UUID, query dto.ExportVisitorsFilter) (VisitorExport, error) {
+	return s.prepareExport(ctx, "", userID, query)
+}
+
+func (s *UrlVisitorService) prepareExport(ctx context.Context, urlID string, userID uuid.UUID, query dto.ExportVisitorsFilter) (VisitorExport, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	from, to, location, err := resolveStatsRange(query.StatsRangeFilter, constants.DefaultStatsRange)
+	if err != nil {
+		return VisitorExport{}, err
+	}
+
+	// Set default format
+	format := query.Format
+	if format == "" {
+		format = constants.DefaultExportFormat
+	}
+
+	// Check if the url exists, unless the whole account is requested
+	if urlID != "" {
+		if err := s.checkUrl(ctx, urlID, userID); err != nil {
+			return VisitorExport{}, err
+		}
+	}
+
+	export := VisitorExport{
+		ContentType: "text/csv; charset=utf-8",
+		urlID:       urlID,
+		from:        from,
+		to:          to,
+		location:    location,
+		format:      format,
+		includeBots: query.IncludeBots,
+
+		urlVisitorRepository: s.urlVisitorRepository,
+	}
+	if userID != uuid.Nil {
+		export.userID = userID.String()
+	}
+	if format == "ndjson" {
+		export.ContentType = "application/x-ndjson"
+	}
+
+	subject := urlID
+	if subject == "" {
+		subject = userID.String()
+	}
+	export.Filename = fmt.Sprintf("visitors-%s-%s-%s.%s", subject, from.In(location).Format(time.DateOnly), to.In(location).Format(time.DateOnly), format)
+
+	return export, nil
+}
+
+// Write streams the exported rows to w, flushing it regularly when it supports flushing.
+// Rows are read from a database cursor, so the export is never held in memory.
+func (e VisitorExport) Write(ctx context.Context, w io.Writer) error {
+	ctx, cancel := context.WithTimeout(ctx, constants.ExportTimeout)
+	defer cancel()
+
+	flusher, _ := w.(http.Flusher)
+	flush := func() {
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	var (
+		writeRow func(model.URLVisitor) error
+		flushRow func() error
+	)
+	if e.format == "ndjson" {
+		encoder := json.NewEncoder(w)
+		writeRow = func(urlVisitor model.URLVisitor) error {
+			urlVisitor.CreatedAt = urlVisitor.CreatedAt.In(e.location)
+			urlVisitor.UpdatedAt = urlVisitor.UpdatedAt.In(e.location)
+			return encoder.Encode(urlVisitor)
+		}
+		flushRow = func() error { return nil }
+	} else {
+		writer := csv.NewWriter(w)
+		if err := writer.Write(visitorExportColumns); err != nil {
+			return err
+		}
+		writeRow = func(urlVisitor model.URLVisitor) error {
+			return writer.Write(visitorExportRecord(urlVisitor, e.location))
+		}
+		flushRow = func() error {
+			writer.Flush()
+			return writer.Error()
+		}
+	}
+
+	count := 0
+	err := e.urlVisitorRepository.EachInRange(ctx, e.urlID, e.userID, e.from, e.to, e.includeBots, func(urlVisitor model.URLVisitor) error {
+		if err := writeRow(urlVisitor); err != nil {
+			return err
+		}
+
+		count++
+		if count%constants.ExportFlushSize == 0 {
+			if err := flushRow(); err != nil {
+				return err
+			}
+			flush()
+		}
+		return nil
+	})
 	if err != nil {
-		logger.Log.Errorw("failed to count url visitors", "error", err)
-		return 0, errs.NewAppError(500, "failed to count url visitors", err)
+		logger.Log.Errorw("failed to export url visitors", "url_id", e.urlID, "user_id", e.userID, "exported", count, "error", err)
+		return err
+	}
+
+	if err := flushRow(); err != nil {
+		return err
+	}
+	flush()
+
+	return nil
+}
+
+// visitorExportRecord returns the CSV fields of a visitor in the order of visitorExportColumns.
+func visitorExportRecord(urlVisitor model.URLVisitor, location *time.Location) []string {
+	ipAddress, ipHash := "", ""
+	if urlVisitor.IpAddress != nil {
+		ipAddress = *urlVisitor.IpAddress
+	}
+	if urlVisitor.IpHash != nil {
+		ipHash = *urlVisitor.IpHash
 	}
 
-	return count, nil
+	return []string{
+		urlVisitor.ID.String(),
+		urlVisitor.UrlID.String(),
+		urlVisitor.CreatedAt.In(location).Format(time.RFC3339),
+		ipAddress,
+		ipHash,
+		csvSafe(urlVisitor.UserAgent),
+		urlVisitor.DeviceType,
+		csvSafe(urlVisitor.OSFamily),
+		csvSafe(urlVisitor.OSVersion),
+		csvSafe(urlVisitor.BrowserFamily),
+		csvSafe(urlVisitor.BrowserVersion),
+		strconv.FormatBool(urlVisitor.IsBot),
+		csvSafe(urlVisitor.ReferrerHost),
+		urlVisitor.Country,
+		csvSafe(urlVisitor.Region),
+		csvSafe(urlVisitor.City),
+	}
+}
+
+// csvSafe prefixes values that spreadsheets would run as a formula with a quote,
+// since user agents and referrers are chosen by the visitor.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
 }
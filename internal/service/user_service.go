@@ -13,12 +13,14 @@ import (
 )
 
 type UserService struct {
-	userRepository *repository.UserRepository
+	userRepository    *repository.UserRepository
+	sessionRepository *repository.SessionRepository
 }
 
-func NewUserService(r *repository.UserRepository) *UserService {
+func NewUserService(r *repository.UserRepository, sessionRepository *repository.SessionRepository) *UserService {
 	return &UserService{
-		userRepository: r,
+		userRepository:    r,
+		sessionRepository: sessionRepository,
 	}
 }
 
@@ -140,7 +142,7 @@ func (s *UserService) UpdateUser(ctx context.Context, request dto.UpdateUserRequ
 	defer cancel()
 
 	// Validate user existence
-	_, err := s.userRepository.GetByID(ctx, request.ID.String())
+	currentUser, err := s.userRepository.GetByID(ctx, request.ID.String())
 	if err != nil {
 		if err == errs.ErrUserNotFound {
 			return err
@@ -177,7 +179,7 @@ func (s *UserService) UpdateUser(ctx context.Context, request dto.UpdateUserRequ
 		ID:       request.ID,
 		Email:    request.Email,
 		Username: request.Username,
-		IsBanned: existingUser.IsBanned,
+		IsBanned: currentUser.IsBanned,
 	}
 
 	// Update user password if provided
@@ -225,30 +227,28 @@ func (s *UserService) CountUsers(ctx context.Context) (int64, error) {
 	return count, nil
 }
 
+// BannedUser bans a user and logs them out everywhere. Incrementing the token version rejects
+// their access tokens at once, and revoking their sessions revokes their refresh tokens.
 func (s *UserService) BannedUser(ctx context.Context, id uuid.UUID) error {
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	// Validate user existence
-	currentUser, err := s.userRepository.GetByID(ctx, id.String())
-	if err != nil {
+	// Ban the user and invalidate their access tokens
+	if err := s.userRepository.Ban(ctx, id.String()); err != nil {
 		if err == errs.ErrUserNotFound {
 			return err
 		}
 
-		logger.Log.Errorw("failed to check user existence for banned", "id", id, "error", err)
-		return errs.NewAppError(500, "failed to validate user", err)
-	}
-
-	// Prepare user data for update
-	currentUser.IsBanned = true
-
-	// Update user
-	if err := s.userRepository.Update(ctx, &currentUser); err != nil {
 		logger.Log.Errorw("failed to banned user", "id", id, "error", err)
 		return errs.NewAppError(500, "failed to update user", err)
 	}
 
+	// Revoke the refresh tokens, banning again retries a failed revocation
+	if _, err := s.sessionRepository.DeleteByUserID(ctx, id.String()); err != nil {
+		logger.Log.Errorw("failed to revoke sessions of banned user", "id", id, "error", err)
+		return errs.NewAppError(500, "failed to revoke user sessions", err)
+	}
+
 	logger.Log.Infow("user banned successfully", "id", id)
 	return nil
 }
@@ -0,0 +1,44 @@
+package apikey
+
+import (
+	"strings"
+
+	"github.com/Alfian57/belajar-golang/internal/utils/hash"
+	"github.com/Alfian57/belajar-golang/internal/utils/shortcode"
+)
+
+// Prefix starts every key, so that keys can be told apart from JWTs and found by secret scanners.
+const Prefix = "blk_"
+
+const (
+	secretLength        = 40
+	displayPrefixLength = len(Prefix) + 8
+)
+
+// Generate returns a new random key.
+func Generate() (string, error) {
+	secret, err := shortcode.Generate(secretLength)
+	if err != nil {
+		return "", err
+	}
+	return Prefix + secret, nil
+}
+
+// Hash returns the hex encoded SHA-256 hash of key. Keys are random enough
+// that a fast unsalted hash is safe to store and look up.
+func Hash(key string) string {
+	return hash.SHA256(key)
+}
+
+// DisplayPrefix returns the start of key, which is stored to identify the key without revealing it.
+func DisplayPrefix(key string) string {
+	if len(key) < displayPrefixLength {
+		return key
+	}
+	return key[:displayPrefixLength]
+}
+
+// IsKey reports whether token looks like an API key rather than a JWT.
+func IsKey(token string) bool {
+	return strings.HasPrefix(token, Prefix)
+}
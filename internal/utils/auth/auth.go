@@ -13,3 +13,18 @@ func GetCurrentUser(ctx *gin.Context) (model.User, bool) {
 	user, ok := u.(model.User)
 	return user, ok
 }
+
+// GetCurrentApiKey returns the API key the request was authenticated with, if any.
+func GetCurrentApiKey(ctx *gin.Context) (model.ApiKey, bool) {
+	k, exists := ctx.Get("api_key")
+	if !exists {
+		return model.ApiKey{}, false
+	}
+	apiKey, ok := k.(model.ApiKey)
+	return apiKey, ok
+}
+
+// GetCurrentSessionID returns the session of the access token the request was authenticated with, if any.
+func GetCurrentSessionID(ctx *gin.Context) string {
+	return ctx.GetString("session_id")
+}
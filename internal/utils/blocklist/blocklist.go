@@ -0,0 +1,198 @@
+package blocklist
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/Alfian57/belajar-golang/internal/model"
+)
+
+const (
+	FormatAuto  = "auto"
+	FormatHosts = "hosts"
+	FormatPlain = "plain"
+	FormatCSV   = "csv"
+)
+
+// bom is the UTF-8 byte order mark some editors prepend to text files.
+const bom = "\ufeff"
+
+var ErrUnknownFormat = errors.New("unknown blocklist format")
+
+// hostsFileDefaults are the entries shipped in every hosts file header,
+// they are not blocklist entries and are silently ignored.
+var hostsFileDefaults = map[string]bool{
+	"localhost":             true,
+	"localhost.localdomain": true,
+	"local":                 true,
+	"broadcasthost":         true,
+	"ip6-localhost":         true,
+	"ip6-loopback":          true,
+	"ip6-localnet":          true,
+	"ip6-mcastprefix":       true,
+	"ip6-allnodes":          true,
+	"ip6-allrouters":        true,
+	"ip6-allhosts":          true,
+	"0.0.0.0":               true,
+}
+
+type Entry struct {
+	Url      string
+	RuleType string
+	Line     int
+}
+
+type Result struct {
+	Entries []Entry
+	// Invalid holds the line numbers that could not be parsed.
+	Invalid []int
+}
+
+// Parse reads a blocklist in hosts-file, plain one-per-line or CSV format.
+// FormatAuto picks CSV when the first entry contains a comma, otherwise
+// hosts-file and plain lines are recognised individually.
+func Parse(r io.Reader, format string) (Result, error) {
+	if format == FormatAuto || format == "" {
+		var err error
+		format, r, err = detectFormat(r)
+		if err != nil {
+			return Result{}, err
+		}
+	}
+
+	switch format {
+	case FormatCSV:
+		return parseCSV(r)
+	case FormatHosts, FormatPlain, FormatAuto:
+		return parseLines(r, format)
+	default:
+		return Result{}, ErrUnknownFormat
+	}
+}
+
+// detectFormat reads up to the first entry and returns FormatCSV or FormatAuto
+// together with a reader that still yields everything that was consumed.
+func detectFormat(r io.Reader) (string, io.Reader, error) {
+	reader := bufio.NewReader(r)
+	var consumed strings.Builder
+
+	for {
+		text, err := reader.ReadString('\n')
+		consumed.WriteString(text)
+
+		if entry := stripComment(strings.TrimPrefix(text, bom)); entry != "" {
+			format := FormatAuto
+			if strings.Contains(entry, ",") {
+				format = FormatCSV
+			}
+			return format, io.MultiReader(strings.NewReader(consumed.String()), reader), nil
+		}
+
+		if err == io.EOF {
+			return FormatAuto, strings.NewReader(consumed.String()), nil
+		}
+		if err != nil {
+			return "", nil, err
+		}
+	}
+}
+
+func parseLines(r io.Reader, format string) (Result, error) {
+	var result Result
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := stripComment(strings.TrimPrefix(scanner.Text(), bom))
+		if text == "" {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		isHostsLine := len(fields) >= 2 && net.ParseIP(fields[0]) != nil
+
+		switch {
+		case isHostsLine && format != FormatPlain:
+			for _, host := range fields[1:] {
+				if hostsFileDefaults[strings.ToLower(host)] {
+					continue
+				}
+				result.Entries = append(result.Entries, Entry{Url: host, RuleType: model.BannedDomainRuleExact, Line: line})
+			}
+		case len(fields) == 1 && format != FormatHosts:
+			result.Entries = append(result.Entries, plainEntry(fields[0], line))
+		default:
+			result.Invalid = append(result.Invalid, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return Result{}, err
+	}
+
+	return result, nil
+}
+
+// parseCSV reads rows of url[,rule_type]. A header row starting with "url" is skipped.
+func parseCSV(r io.Reader) (Result, error) {
+	var result Result
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				result.Invalid = append(result.Invalid, parseErr.Line)
+				continue
+			}
+			return Result{}, err
+		}
+
+		pattern := strings.TrimSpace(strings.TrimPrefix(record[0], bom))
+		if pattern == "" {
+			continue
+		}
+		if strings.EqualFold(pattern, "url") && len(result.Entries) == 0 {
+			continue
+		}
+
+		entry := plainEntry(pattern, line)
+		if len(record) > 1 && strings.TrimSpace(record[1]) != "" {
+			entry.RuleType = strings.ToLower(strings.TrimSpace(record[1]))
+		}
+		result.Entries = append(result.Entries, entry)
+	}
+
+	return result, nil
+}
+
+// plainEntry turns a single domain into an entry, treating *.example.com as a wildcard rule.
+func plainEntry(pattern string, line int) Entry {
+	if strings.HasPrefix(pattern, "*.") {
+		return Entry{Url: pattern, RuleType: model.BannedDomainRuleWildcard, Line: line}
+	}
+	return Entry{Url: pattern, RuleType: model.BannedDomainRuleExact, Line: line}
+}
+
+func stripComment(line string) string {
+	if i := strings.IndexByte(line, '#'); i >= 0 {
+		line = line[:i]
+	}
+	return strings.TrimSpace(line)
+}
@@ -0,0 +1,105 @@
+package blocklist
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Alfian57/belajar-golang/internal/model"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		format  string
+		entries []Entry
+		invalid []int
+	}{
+		{
+			name: "auto detects hosts file",
+			input: "# hosts file\n" +
+				"127.0.0.1 localhost\n" +
+				"0.0.0.0 ads.example.com tracker.example.com\n" +
+				"::1 ip6-localhost\n",
+			format: FormatAuto,
+			entries: []Entry{
+				{Url: "ads.example.com", RuleType: model.BannedDomainRuleExact, Line: 3},
+				{Url: "tracker.example.com", RuleType: model.BannedDomainRuleExact, Line: 3},
+			},
+		},
+		{
+			name:   "auto detects plain list",
+			input:  "\ufeffbad.example.com\n\n*.evil.example.org # wildcard\n",
+			format: "",
+			entries: []Entry{
+				{Url: "bad.example.com", RuleType: model.BannedDomainRuleExact, Line: 1},
+				{Url: "*.evil.example.org", RuleType: model.BannedDomainRuleWildcard, Line: 3},
+			},
+		},
+		{
+			name:   "auto mixes hosts and plain lines",
+			input:  "0.0.0.0 ads.example.com\nbad.example.com\ntoo many words\n",
+			format: FormatAuto,
+			entries: []Entry{
+				{Url: "ads.example.com", RuleType: model.BannedDomainRuleExact, Line: 1},
+				{Url: "bad.example.com", RuleType: model.BannedDomainRuleExact, Line: 2},
+			},
+			invalid: []int{3},
+		},
+		{
+			name:   "auto detects csv after comments",
+			input:  "# exported rules\nurl,rule_type\nbad.example.com,exact\n*.evil.example.org,\nexample.net/bad, PATH_PREFIX\n",
+			format: FormatAuto,
+			entries: []Entry{
+				{Url: "bad.example.com", RuleType: model.BannedDomainRuleExact, Line: 3},
+				{Url: "*.evil.example.org", RuleType: model.BannedDomainRuleWildcard, Line: 4},
+				{Url: "example.net/bad", RuleType: model.BannedDomainRulePathPrefix, Line: 5},
+			},
+		},
+		{
+			name:   "hosts format rejects plain lines",
+			input:  "0.0.0.0 ads.example.com\nbad.example.com\n",
+			format: FormatHosts,
+			entries: []Entry{
+				{Url: "ads.example.com", RuleType: model.BannedDomainRuleExact, Line: 1},
+			},
+			invalid: []int{2},
+		},
+		{
+			name:    "plain format rejects hosts lines",
+			input:   "0.0.0.0 ads.example.com\nbad.example.com\n",
+			format:  FormatPlain,
+			entries: []Entry{{Url: "bad.example.com", RuleType: model.BannedDomainRuleExact, Line: 2}},
+			invalid: []int{1},
+		},
+		{
+			name:   "empty input",
+			input:  "# nothing here\n\n",
+			format: FormatAuto,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(strings.NewReader(tt.input), tt.format)
+			if err != nil {
+				t.Fatalf("Parse returned error: %v", err)
+			}
+			if !reflect.DeepEqual(result.Entries, tt.entries) {
+				t.Errorf("Parse entries = %+v, want %+v", result.Entries, tt.entries)
+			}
+			if !reflect.DeepEqual(result.Invalid, tt.invalid) {
+				t.Errorf("Parse invalid = %v, want %v", result.Invalid, tt.invalid)
+			}
+		})
+	}
+}
+
+func TestParseUnknownFormat(t *testing.T) {
+	_, err := Parse(strings.NewReader("bad.example.com\n"), "xml")
+	if !errors.Is(err, ErrUnknownFormat) {
+		t.Fatalf("Parse error = %v, want %v", err, ErrUnknownFormat)
+	}
+}
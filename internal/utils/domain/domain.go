@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+var ErrInvalidUrl = errors.New("url has no valid host")
+
+// hostProfile converts hosts like idna.Lookup but without the STD3 rules,
+// which would reject hosts with underscores that resolve fine in practice.
+// Empty and overlong labels are still rejected.
+var hostProfile = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.StrictDomainName(false),
+	idna.VerifyDNSLength(true),
+)
+
+// Parse parses rawUrl, adding an http scheme when none is given,
+// and normalises its host with NormalizeHost.
+func Parse(rawUrl string) (*url.URL, error) {
+	rawUrl = strings.TrimSpace(rawUrl)
+	if !strings.Contains(rawUrl, "://") {
+		rawUrl = "http://" + rawUrl
+	}
+
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidUrl, err)
+	}
+
+	host, err := NormalizeHost(u.Hostname())
+	if err != nil {
+		return nil, err
+	}
+	u.Host = host
+
+	return u, nil
+}
+
+// NormalizeHost lower-cases host, converts it to its ASCII (punycode) form
+// and strips the trailing dot so that equivalent hosts compare equal.
+// IP addresses are returned in their canonical form, IPv6 addresses in brackets.
+func NormalizeHost(host string) (string, error) {
+	host = strings.TrimSpace(host)
+	if ip, err := netip.ParseAddr(strings.Trim(host, "[]")); err == nil && ip.Zone() == "" {
+		if ip.Is6() && !ip.Is4In6() {
+			return "[" + ip.String() + "]", nil
+		}
+		return ip.Unmap().String(), nil
+	}
+
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" {
+		return "", ErrInvalidUrl
+	}
+
+	ascii, err := hostProfile.ToASCII(host)
+	if err != nil {
+		return "", ErrInvalidUrl
+	}
+
+	return ascii, nil
+}
+
+// Host returns the normalised host of rawUrl.
+func Host(rawUrl string) (string, error) {
+	u, err := Parse(rawUrl)
+	if err != nil {
+		return "", err
+	}
+	return u.Host, nil
+}
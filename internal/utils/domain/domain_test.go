@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHost(t *testing.T) {
+	tests := []struct {
+		name   string
+		rawUrl string
+		want   string
+	}{
+		{name: "lower-cases the host", rawUrl: "https://Example.COM/path", want: "example.com"},
+		{name: "adds a missing scheme", rawUrl: "example.com/path", want: "example.com"},
+		{name: "strips the trailing dot", rawUrl: "https://example.com./", want: "example.com"},
+		{name: "strips the port", rawUrl: "https://example.com:8080/", want: "example.com"},
+		{name: "converts unicode to punycode", rawUrl: "https://bücher.example/", want: "xn--bcher-kva.example"},
+		{name: "keeps underscores", rawUrl: "https://my_host.example.com/", want: "my_host.example.com"},
+		{name: "ipv4 address", rawUrl: "http://192.168.1.10:8080/", want: "192.168.1.10"},
+		{name: "ipv6 address", rawUrl: "http://[::1]:8080/", want: "[::1]"},
+		{name: "ipv6 address is canonicalised", rawUrl: "http://[2001:DB8:0:0::1]/", want: "[2001:db8::1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Host(tt.rawUrl)
+			if err != nil {
+				t.Fatalf("Host(%q) returned error: %v", tt.rawUrl, err)
+			}
+			if got != tt.want {
+				t.Errorf("Host(%q) = %q, want %q", tt.rawUrl, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHostInvalid(t *testing.T) {
+	for _, rawUrl := range []string{"", "http://", "http://%zz/", "http://a..b/"} {
+		if _, err := Host(rawUrl); !errors.Is(err, ErrInvalidUrl) {
+			t.Errorf("Host(%q) error = %v, want %v", rawUrl, err, ErrInvalidUrl)
+		}
+	}
+}
+
+func TestParseKeepsIpv6UsableAsUrl(t *testing.T) {
+	u, err := Parse("http://[::1]:8080/path")
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if got := u.String(); got != "http://[::1]/path" {
+		t.Errorf("Parse(...).String() = %q, want %q", got, "http://[::1]/path")
+	}
+}
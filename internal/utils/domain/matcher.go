@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/Alfian57/belajar-golang/internal/model"
+)
+
+var ErrInvalidRule = errors.New("invalid banned domain rule")
+
+type pathRule struct {
+	prefix string
+	rule   model.BannedDomain
+}
+
+type regexRule struct {
+	pattern *regexp.Regexp
+	rule    model.BannedDomain
+}
+
+// Matcher evaluates a set of banned domain rules against urls in memory.
+type Matcher struct {
+	exact      map[string]model.BannedDomain
+	wildcard   map[string]model.BannedDomain
+	pathPrefix map[string][]pathRule
+	regex      []regexRule
+	invalid    []model.BannedDomain
+}
+
+// NewMatcher compiles the given rules. Rules that cannot be compiled are
+// skipped and reported by Invalid.
+func NewMatcher(rules []model.BannedDomain) *Matcher {
+	m := &Matcher{
+		exact:      make(map[string]model.BannedDomain),
+		wildcard:   make(map[string]model.BannedDomain),
+		pathPrefix: make(map[string][]pathRule),
+	}
+
+	for _, rule := range rules {
+		if err := m.add(rule); err != nil {
+			m.invalid = append(m.invalid, rule)
+		}
+	}
+
+	return m
+}
+
+// ValidateRule checks that pattern is usable for the given rule type.
+func ValidateRule(ruleType string, pattern string) error {
+	return NewMatcher(nil).add(model.BannedDomain{RuleType: ruleType, URL: pattern})
+}
+
+// Invalid returns the rules that were skipped because they could not be compiled.
+func (m *Matcher) Invalid() []model.BannedDomain {
+	return m.invalid
+}
+
+// Match returns the first rule that bans u.
+func (m *Matcher) Match(u *url.URL) (model.BannedDomain, bool) {
+	host := u.Host
+
+	if rule, ok := m.exact[host]; ok {
+		return rule, true
+	}
+
+	// Walk up the parent domains: a.b.example.com, b.example.com, example.com, com
+	for candidate := host; candidate != ""; {
+		if rule, ok := m.wildcard[candidate]; ok {
+			return rule, true
+		}
+		i := strings.IndexByte(candidate, '.')
+		if i < 0 {
+			break
+		}
+		candidate = candidate[i+1:]
+	}
+
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	for _, pr := range m.pathPrefix[host] {
+		if hasPathPrefix(path, pr.prefix) {
+			return pr.rule, true
+		}
+	}
+
+	if len(m.regex) > 0 {
+		target := u.String()
+		for _, rr := range m.regex {
+			if rr.pattern.MatchString(target) {
+				return rr.rule, true
+			}
+		}
+	}
+
+	return model.BannedDomain{}, false
+}
+
+// MatchString parses rawUrl and returns the first rule that bans it.
+func (m *Matcher) MatchString(rawUrl string) (model.BannedDomain, bool, error) {
+	u, err := Parse(rawUrl)
+	if err != nil {
+		return model.BannedDomain{}, false, err
+	}
+
+	rule, ok := m.Match(u)
+	return rule, ok, nil
+}
+
+func (m *Matcher) add(rule model.BannedDomain) error {
+	pattern := strings.TrimSpace(rule.URL)
+
+	switch rule.RuleType {
+	case model.BannedDomainRuleExact, "":
+		host, err := Host(pattern)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidRule, err)
+		}
+		m.exact[host] = rule
+
+	case model.BannedDomainRuleWildcard:
+		host, err := Host(strings.TrimPrefix(pattern, "*."))
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidRule, err)
+		}
+		m.wildcard[host] = rule
+
+	case model.BannedDomainRulePathPrefix:
+		u, err := Parse(pattern)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidRule, err)
+		}
+		prefix := u.EscapedPath()
+		if prefix == "" {
+			prefix = "/"
+		}
+		m.pathPrefix[u.Host] = append(m.pathPrefix[u.Host], pathRule{prefix: prefix, rule: rule})
+
+	case model.BannedDomainRuleRegex:
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidRule, err)
+		}
+		m.regex = append(m.regex, regexRule{pattern: re, rule: rule})
+
+	default:
+		return fmt.Errorf("%w: unknown rule type %q", ErrInvalidRule, rule.RuleType)
+	}
+
+	return nil
+}
+
+// hasPathPrefix reports whether path starts with prefix on a segment boundary,
+// so that /bad matches /bad and /bad/page but not /badge.
+func hasPathPrefix(path string, prefix string) bool {
+	if !strings.HasPrefix(path, prefix) {
+		return false
+	}
+	return len(path) == len(prefix) || strings.HasSuffix(prefix, "/") || path[len(prefix)] == '/'
+}
@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/Alfian57/belajar-golang/internal/model"
+)
+
+func TestMatcherMatchString(t *testing.T) {
+	rules := []model.BannedDomain{
+		{URL: "exact.example.com", RuleType: model.BannedDomainRuleExact},
+		{URL: "*.wild.example.org", RuleType: model.BannedDomainRuleWildcard},
+		{URL: "paths.example.net/bad", RuleType: model.BannedDomainRulePathPrefix},
+		{URL: "paths.example.net/folder/", RuleType: model.BannedDomainRulePathPrefix},
+		{URL: `^https?://[^/]+/phish-\d+`, RuleType: model.BannedDomainRuleRegex},
+	}
+	matcher := NewMatcher(rules)
+
+	tests := []struct {
+		name   string
+		url    string
+		banned bool
+		rule   string
+	}{
+		{name: "exact host", url: "https://exact.example.com/page", banned: true, rule: "exact.example.com"},
+		{name: "exact host is normalised", url: "HTTP://Exact.Example.COM./", banned: true, rule: "exact.example.com"},
+		{name: "exact rule ignores subdomains", url: "https://sub.exact.example.com", banned: false},
+		{name: "exact rule ignores parent", url: "https://example.com", banned: false},
+
+		{name: "wildcard matches its domain", url: "https://wild.example.org", banned: true, rule: "*.wild.example.org"},
+		{name: "wildcard matches a subdomain", url: "https://a.wild.example.org", banned: true, rule: "*.wild.example.org"},
+		{name: "wildcard walks up nested subdomains", url: "https://a.b.c.wild.example.org/x", banned: true, rule: "*.wild.example.org"},
+		{name: "wildcard ignores parent domain", url: "https://example.org", banned: false},
+		{name: "wildcard ignores lookalike suffix", url: "https://notwild.example.org", banned: false},
+
+		{name: "path prefix matches the path itself", url: "https://paths.example.net/bad", banned: true, rule: "paths.example.net/bad"},
+		{name: "path prefix matches a sub path", url: "https://paths.example.net/bad/page", banned: true, rule: "paths.example.net/bad"},
+		{name: "path prefix stops at segment boundary", url: "https://paths.example.net/badge", banned: false},
+		{name: "path prefix with trailing slash", url: "https://paths.example.net/folder/file", banned: true, rule: "paths.example.net/folder/"},
+		{name: "path prefix with trailing slash needs it", url: "https://paths.example.net/folder", banned: false},
+		{name: "path prefix ignores other paths", url: "https://paths.example.net/good", banned: false},
+		{name: "path prefix ignores other hosts", url: "https://other.example.net/bad", banned: false},
+
+		{name: "regex matches", url: "https://anything.test/phish-42", banned: true, rule: `^https?://[^/]+/phish-\d+`},
+		{name: "regex does not match", url: "https://anything.test/phish-x", banned: false},
+
+		{name: "scheme is optional", url: "exact.example.com/page", banned: true, rule: "exact.example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, banned, err := matcher.MatchString(tt.url)
+			if err != nil {
+				t.Fatalf("MatchString(%q) returned error: %v", tt.url, err)
+			}
+			if banned != tt.banned {
+				t.Fatalf("MatchString(%q) banned = %v, want %v", tt.url, banned, tt.banned)
+			}
+			if banned && rule.URL != tt.rule {
+				t.Errorf("MatchString(%q) rule = %q, want %q", tt.url, rule.URL, tt.rule)
+			}
+		})
+	}
+}
+
+func TestMatcherMatchStringInvalidUrl(t *testing.T) {
+	matcher := NewMatcher(nil)
+
+	for _, rawUrl := range []string{"", "   ", "http://"} {
+		if _, _, err := matcher.MatchString(rawUrl); err == nil {
+			t.Errorf("MatchString(%q) returned no error", rawUrl)
+		}
+	}
+}
+
+func TestNewMatcherInvalidRules(t *testing.T) {
+	rules := []model.BannedDomain{
+		{URL: "example.com", RuleType: model.BannedDomainRuleExact},
+		{URL: "[unclosed", RuleType: model.BannedDomainRuleRegex},
+		{URL: "example.com", RuleType: "unknown"},
+		{URL: "", RuleType: model.BannedDomainRuleWildcard},
+	}
+
+	invalid := NewMatcher(rules).Invalid()
+	if len(invalid) != 3 {
+		t.Fatalf("Invalid() returned %d rules, want 3", len(invalid))
+	}
+}
+
+func TestHasPathPrefix(t *testing.T) {
+	tests := []struct {
+		path   string
+		prefix string
+		want   bool
+	}{
+		{path: "/bad", prefix: "/bad", want: true},
+		{path: "/bad/page", prefix: "/bad", want: true},
+		{path: "/badge", prefix: "/bad", want: false},
+		{path: "/bad/", prefix: "/bad/", want: true},
+		{path: "/anything", prefix: "/", want: true},
+		{path: "/", prefix: "/bad", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := hasPathPrefix(tt.path, tt.prefix); got != tt.want {
+			t.Errorf("hasPathPrefix(%q, %q) = %v, want %v", tt.path, tt.prefix, got, tt.want)
+		}
+	}
+}
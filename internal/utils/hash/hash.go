@@ -1,6 +1,9 @@
 package hash
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -13,3 +16,10 @@ func CheckPasswordHash(password string, hash string) error {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
 	return err
 }
+
+// SHA256 returns the hex encoded SHA-256 hash of value. It is only suitable
+// for random secrets such as tokens, passwords need HashPassword.
+func SHA256(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
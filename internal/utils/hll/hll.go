@@ -0,0 +1,105 @@
+package hll
+
+import (
+	"errors"
+	"hash/fnv"
+	"math"
+	"math/bits"
+)
+
+const (
+	version = 1
+	// precision gives 4096 registers, a standard error of about 1.6% and 4 KiB per sketch.
+	precision    = 12
+	registers    = 1 << precision
+	headerLength = 2
+)
+
+var ErrInvalidSketch = errors.New("invalid hyperloglog sketch")
+
+// Sketch is a dense HyperLogLog sketch that estimates the number of distinct values added to it.
+// Sketches can be merged, so counts over several days are the count of the merged daily sketches.
+type Sketch struct {
+	registers []uint8
+}
+
+func New() *Sketch {
+	return &Sketch{registers: make([]uint8, registers)}
+}
+
+// FromBytes decodes a sketch encoded with Bytes.
+func FromBytes(data []byte) (*Sketch, error) {
+	if len(data) != headerLength+registers || data[0] != version || data[1] != precision {
+		return nil, ErrInvalidSketch
+	}
+
+	sketch := New()
+	copy(sketch.registers, data[headerLength:])
+	return sketch, nil
+}
+
+// Bytes encodes the sketch as a version byte, a precision byte and the registers.
+func (s *Sketch) Bytes() []byte {
+	data := make([]byte, headerLength+registers)
+	data[0] = version
+	data[1] = precision
+	copy(data[headerLength:], s.registers)
+	return data
+}
+
+// AddString adds value to the sketch.
+func (s *Sketch) AddString(value string) {
+	h := fnv.New64a()
+	h.Write([]byte(value))
+	s.add(mix(h.Sum64()))
+}
+
+// Merge adds all values of other to the sketch.
+func (s *Sketch) Merge(other *Sketch) {
+	for i, register := range other.registers {
+		if register > s.registers[i] {
+			s.registers[i] = register
+		}
+	}
+}
+
+// Count estimates the number of distinct values added to the sketch.
+func (s *Sketch) Count() uint64 {
+	m := float64(registers)
+	sum := 0.0
+	zeros := 0
+	for _, register := range s.registers {
+		sum += math.Ldexp(1, -int(register))
+		if register == 0 {
+			zeros++
+		}
+	}
+
+	alpha := 0.7213 / (1 + 1.079/m)
+	estimate := alpha * m * m / sum
+
+	// Use linear counting for small cardinalities, where the raw estimate is biased
+	if estimate <= 2.5*m && zeros > 0 {
+		estimate = m * math.Log(m/float64(zeros))
+	}
+
+	return uint64(estimate + 0.5)
+}
+
+func (s *Sketch) add(hash uint64) {
+	index := hash >> (64 - precision)
+	rank := uint8(bits.LeadingZeros64(hash<<precision|1<<(precision-1)) + 1)
+	if rank > s.registers[index] {
+		s.registers[index] = rank
+	}
+}
+
+// mix spreads the bits of an FNV hash, which on its own is too weak for HyperLogLog.
+func mix(hash uint64) uint64 {
+	hash ^= hash >> 33
+	hash *= 0xff51afd7ed558ccd
+	hash ^= hash >> 33
+	hash *= 0xc4ceb9fe1a85ec53
+	hash ^= hash >> 33
+	return hash
+}
@@ -0,0 +1,133 @@
+package hll
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+)
+
+// within reports whether count is at most 5% off, about three standard errors.
+func within(count uint64, want int) bool {
+	return math.Abs(float64(count)-float64(want)) <= 0.05*float64(want)
+}
+
+func newSketch(prefix string, from int, to int) *Sketch {
+	sketch := New()
+	for i := from; i < to; i++ {
+		sketch.AddString(fmt.Sprintf("%s-%d", prefix, i))
+	}
+	return sketch
+}
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		distinct int
+	}{
+		{name: "empty", distinct: 0},
+		{name: "small", distinct: 100},
+		{name: "medium", distinct: 10000},
+		{name: "large", distinct: 200000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count := newSketch("visitor", 0, tt.distinct).Count()
+			if tt.distinct == 0 && count != 0 {
+				t.Fatalf("Count() = %d, want 0", count)
+			}
+			if !within(count, tt.distinct) {
+				t.Errorf("Count() = %d, want about %d", count, tt.distinct)
+			}
+		})
+	}
+}
+
+func TestCountIgnoresDuplicates(t *testing.T) {
+	sketch := New()
+	for range 10 {
+		for i := range 1000 {
+			sketch.AddString(fmt.Sprintf("visitor-%d", i))
+		}
+	}
+
+	if count := sketch.Count(); !within(count, 1000) {
+		t.Errorf("Count() = %d, want about 1000", count)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name  string
+		left  [2]int
+		right [2]int
+		want  int
+	}{
+		{name: "disjoint", left: [2]int{0, 5000}, right: [2]int{5000, 10000}, want: 10000},
+		{name: "overlapping", left: [2]int{0, 6000}, right: [2]int{4000, 10000}, want: 10000},
+		{name: "identical", left: [2]int{0, 5000}, right: [2]int{0, 5000}, want: 5000},
+		{name: "into empty", left: [2]int{0, 0}, right: [2]int{0, 3000}, want: 3000},
+		{name: "with empty", left: [2]int{0, 3000}, right: [2]int{0, 0}, want: 3000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := newSketch("visitor", tt.left[0], tt.left[1])
+			merged.Merge(newSketch("visitor", tt.right[0], tt.right[1]))
+
+			if count := merged.Count(); !within(count, tt.want) {
+				t.Errorf("Count() after Merge = %d, want about %d", count, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeEqualsSingleSketch(t *testing.T) {
+	merged := newSketch("visitor", 0, 4000)
+	merged.Merge(newSketch("visitor", 2000, 8000))
+
+	single := newSketch("visitor", 0, 8000)
+	if merged.Count() != single.Count() {
+		t.Errorf("merged Count() = %d, want %d like a single sketch", merged.Count(), single.Count())
+	}
+}
+
+func TestBytesRoundTrip(t *testing.T) {
+	sketch := newSketch("visitor", 0, 1000)
+
+	decoded, err := FromBytes(sketch.Bytes())
+	if err != nil {
+		t.Fatalf("FromBytes returned error: %v", err)
+	}
+	if decoded.Count() != sketch.Count() {
+		t.Errorf("decoded Count() = %d, want %d", decoded.Count(), sketch.Count())
+	}
+}
+
+func TestFromBytesInvalid(t *testing.T) {
+	valid := New().Bytes()
+
+	wrongVersion := append([]byte(nil), valid...)
+	wrongVersion[0] = version + 1
+	wrongPrecision := append([]byte(nil), valid...)
+	wrongPrecision[1] = precision + 1
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "empty", data: nil},
+		{name: "truncated", data: valid[:len(valid)-1]},
+		{name: "wrong version", data: wrongVersion},
+		{name: "wrong precision", data: wrongPrecision},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FromBytes(tt.data); !errors.Is(err, ErrInvalidSketch) {
+				t.Errorf("FromBytes error = %v, want %v", err, ErrInvalidSketch)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package ipanon
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/netip"
+)
+
+const (
+	// ModeFull stores the address as it is.
+	ModeFull = "full"
+	// ModeTruncate zeroes the last octet of IPv4 and the last 80 bits of IPv6 addresses.
+	ModeTruncate = "truncate"
+	// ModeHash replaces the address with a keyed HMAC hash.
+	ModeHash = "hash"
+)
+
+const (
+	ipv4PrefixBits = 24
+	ipv6PrefixBits = 48
+)
+
+// Anonymize applies mode to ip. It returns the address to store, if any, and its hash, if any.
+// Both are empty when ip is not a valid address or mode is unknown.
+func Anonymize(ip string, mode string, secret string) (address string, hash string) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return "", ""
+	}
+	addr = addr.Unmap().WithZone("")
+
+	switch mode {
+	case ModeFull:
+		return addr.String(), ""
+	case ModeTruncate:
+		return Truncate(addr).String(), ""
+	case ModeHash:
+		return "", Hash(addr, secret)
+	}
+	return "", ""
+}
+
+// Truncate keeps the network part of addr, /24 for IPv4 and /48 for IPv6.
+func Truncate(addr netip.Addr) netip.Addr {
+	bits := ipv6PrefixBits
+	if addr.Is4() {
+		bits = ipv4PrefixBits
+	}
+
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return netip.Addr{}
+	}
+	return prefix.Addr()
+}
+
+// Hash returns the hex encoded HMAC-SHA256 of addr keyed with secret.
+func Hash(addr netip.Addr, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(addr.String()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsValidMode reports whether mode is one of the supported anonymisation modes.
+func IsValidMode(mode string) bool {
+	return mode == ModeFull || mode == ModeTruncate || mode == ModeHash
+}
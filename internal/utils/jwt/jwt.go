@@ -8,12 +8,24 @@ import (
 	"github.com/Alfian57/belajar-golang/internal/model"
 	"github.com/golang-jwt/jwt/v5"
 	golangJwt "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
-func CreateAccessToken(user model.User) (string, error) {
+// AccessTokenClaims holds the claims of a valid access token.
+type AccessTokenClaims struct {
+	UserID       string
+	SessionID    string
+	TokenVersion int
+}
+
+// CreateAccessToken creates an access token of the given session, which is stored in the "sid" claim.
+// The token version of the user is stored in the "ver" claim.
+func CreateAccessToken(user model.User, sessionID uuid.UUID) (string, error) {
 
 	token := golangJwt.NewWithClaims(golangJwt.SigningMethodHS256, golangJwt.MapClaims{
 		"id":       user.ID,
+		"sid":      sessionID,
+		"ver":      user.TokenVersion,
 		"username": user.Username,
 		"exp":      time.Now().Add(time.Minute * 15).Unix(),
 	})
@@ -25,10 +37,14 @@ func CreateAccessToken(user model.User) (string, error) {
 	return tokenString, err
 }
 
-func CreateRefreshToken(user model.User) (string, error) {
+// CreateRefreshToken creates a refresh token of the given family. Every token gets
+// its own "jti", so that tokens issued in the same second still differ.
+func CreateRefreshToken(user model.User, familyID uuid.UUID) (string, error) {
 
 	token := golangJwt.NewWithClaims(golangJwt.SigningMethodHS256, golangJwt.MapClaims{
 		"id":  user.ID,
+		"fid": familyID,
+		"jti": uuid.NewString(),
 		"exp": time.Now().Add(time.Hour * 24 * 7).Unix(),
 	})
 
@@ -39,7 +55,7 @@ func CreateRefreshToken(user model.User) (string, error) {
 	return tokenString, err
 }
 
-func ValidateAccessToken(tokenString string) (string, error) {
+func ValidateAccessToken(tokenString string) (AccessTokenClaims, error) {
 	token, err := jwt.Parse(tokenString, func(token *golangJwt.Token) (any, error) {
 		secret := config.GetEnv("ACCESS_TOKEN_SECRET", "secret")
 		secretByte := []byte(secret)
@@ -47,17 +63,27 @@ func ValidateAccessToken(tokenString string) (string, error) {
 	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 
 	if err != nil {
-		return "", err
+		return AccessTokenClaims{}, err
 	}
 
 	if claims, ok := token.Claims.(golangJwt.MapClaims); ok {
-		if id, ok := claims["id"].(string); ok {
-			return id, nil
+		id, ok := claims["id"].(string)
+		if !ok {
+			return AccessTokenClaims{}, errs.ErrInvalidTokenClaims
 		}
-		return "", errs.ErrInvalidTokenClaims
+		sid, ok := claims["sid"].(string)
+		if !ok {
+			return AccessTokenClaims{}, errs.ErrInvalidTokenClaims
+		}
+		// Numbers are decoded as float64
+		ver, ok := claims["ver"].(float64)
+		if !ok {
+			return AccessTokenClaims{}, errs.ErrInvalidTokenClaims
+		}
+		return AccessTokenClaims{UserID: id, SessionID: sid, TokenVersion: int(ver)}, nil
 	}
 
-	return "", err
+	return AccessTokenClaims{}, err
 }
 
 func GetUserID(tokenString string) (string, error) {
@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+type entry struct {
+	hits      int
+	expiresAt time.Time
+}
+
+// Limiter counts hits per key in fixed windows and refuses keys that reached the limit.
+// It is safe for concurrent use and keeps its state in memory.
+type Limiter struct {
+	mu      sync.Mutex
+	limit   int
+	window  time.Duration
+	entries map[string]*entry
+	sweepAt time.Time
+}
+
+func New(limit int, window time.Duration) *Limiter {
+	return &Limiter{
+		limit:   limit,
+		window:  window,
+		entries: make(map[string]*entry),
+		sweepAt: time.Now().Add(window),
+	}
+}
+
+// Allow reports whether key is still below the limit in its current window.
+func (l *Limiter) Allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	e := l.current(key, time.Now())
+	return e == nil || e.hits < l.limit
+}
+
+// Hit records a hit for key, starting a new window when the previous one expired.
+func (l *Limiter) Hit(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	e := l.current(key, now)
+	if e == nil {
+		e = &entry{expiresAt: now.Add(l.window)}
+		l.entries[key] = e
+	}
+	e.hits++
+
+	l.sweep(now)
+}
+
+// Reset forgets all hits recorded for key.
+func (l *Limiter) Reset(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.entries, key)
+}
+
+func (l *Limiter) current(key string, now time.Time) *entry {
+	e, ok := l.entries[key]
+	if !ok {
+		return nil
+	}
+	if now.After(e.expiresAt) {
+		delete(l.entries, key)
+		return nil
+	}
+	return e
+}
+
+// sweep drops expired entries at most once per window so that the map does not grow forever.
+func (l *Limiter) sweep(now time.Time) {
+	if now.Before(l.sweepAt) {
+		return
+	}
+
+	for key, e := range l.entries {
+		if now.After(e.expiresAt) {
+			delete(l.entries, key)
+		}
+	}
+	l.sweepAt = now.Add(l.window)
+}
@@ -0,0 +1,44 @@
+package shortcode
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+const alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// Generate returns a random base62 code of the given length.
+func Generate(length int) (string, error) {
+	max := big.NewInt(int64(len(alphabet)))
+	code := make([]byte, length)
+
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		code[i] = alphabet[n.Int64()]
+	}
+
+	return string(code), nil
+}
+
+// IsValid reports whether code only contains letters, digits, dashes and underscores.
+func IsValid(code string) bool {
+	if code == "" {
+		return false
+	}
+
+	for _, r := range code {
+		switch {
+		case r >= '0' && r <= '9':
+		case r >= 'A' && r <= 'Z':
+		case r >= 'a' && r <= 'z':
+		case r == '-' || r == '_':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
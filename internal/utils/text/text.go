@@ -0,0 +1,22 @@
+package text
+
+import "unicode/utf8"
+
+// Truncate cuts value to at most length bytes without splitting a multi-byte character.
+func Truncate(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+	for length > 0 && !utf8.RuneStart(value[length]) {
+		length--
+	}
+	return value[:length]
+}
+
+// NullableString returns nil for an empty value so that it is stored as NULL.
+func NullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
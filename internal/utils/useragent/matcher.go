@@ -0,0 +1,31 @@
+package useragent
+
+import "strings"
+
+// Matcher flags User-Agent headers that contain any of a set of patterns, ignoring case.
+type Matcher struct {
+	patterns []string
+}
+
+// NewMatcher returns a matcher for the given patterns. Blank patterns are ignored.
+func NewMatcher(patterns []string) *Matcher {
+	m := &Matcher{}
+	for _, pattern := range patterns {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern != "" {
+			m.patterns = append(m.patterns, pattern)
+		}
+	}
+	return m
+}
+
+// Match reports whether ua contains any of the patterns.
+func (m *Matcher) Match(ua string) bool {
+	lower := strings.ToLower(ua)
+	for _, pattern := range m.patterns {
+		if strings.Contains(lower, pattern) {
+			return true
+		}
+	}
+	return false
+}
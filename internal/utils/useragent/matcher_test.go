@@ -0,0 +1,33 @@
+package useragent
+
+import "testing"
+
+func TestMatcherMatch(t *testing.T) {
+	matcher := NewMatcher([]string{"UptimeRobot", "  internal-checker ", "", "   "})
+
+	tests := []struct {
+		name string
+		ua   string
+		want bool
+	}{
+		{name: "exact pattern", ua: "UptimeRobot/2.0", want: true},
+		{name: "ignores case", ua: "Mozilla/5.0 (compatible; uptimerobot/2.0)", want: true},
+		{name: "trimmed pattern", ua: "Internal-Checker v3", want: true},
+		{name: "no pattern", ua: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) Firefox/121.0", want: false},
+		{name: "blank patterns match nothing", ua: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matcher.Match(tt.ua); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.ua, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcherWithoutPatterns(t *testing.T) {
+	if NewMatcher(nil).Match("Googlebot/2.1") {
+		t.Error("Match on an empty matcher = true, want false")
+	}
+}
@@ -0,0 +1,228 @@
+package useragent
+
+import (
+	"strings"
+)
+
+const (
+	DeviceDesktop = "desktop"
+	DeviceMobile  = "mobile"
+	DeviceTablet  = "tablet"
+	DeviceBot     = "bot"
+	DeviceUnknown = "unknown"
+)
+
+// Other is used as family when the operating system or browser is not recognised.
+const Other = "Other"
+
+// Info holds the dimensions extracted from a User-Agent header.
+type Info struct {
+	DeviceType     string
+	OSFamily       string
+	OSVersion      string
+	BrowserFamily  string
+	BrowserVersion string
+	IsBot          bool
+}
+
+type family struct {
+	name  string
+	token string
+}
+
+// knownBots are matched case-insensitively and reported as the browser family.
+var knownBots = []family{
+	{"Googlebot", "googlebot"},
+	{"Bingbot", "bingbot"},
+	{"DuckDuckBot", "duckduckbot"},
+	{"Baiduspider", "baiduspider"},
+	{"YandexBot", "yandexbot"},
+	{"Applebot", "applebot"},
+	{"AhrefsBot", "ahrefsbot"},
+	{"SemrushBot", "semrushbot"},
+	{"Facebook", "facebookexternalhit"},
+	{"Twitterbot", "twitterbot"},
+	{"LinkedInBot", "linkedinbot"},
+	{"Slackbot", "slackbot"},
+	{"Discordbot", "discordbot"},
+	{"TelegramBot", "telegrambot"},
+	{"WhatsApp", "whatsapp"},
+	{"curl", "curl/"},
+	{"Wget", "wget/"},
+	{"Python Requests", "python-requests/"},
+	{"Go HTTP Client", "go-http-client/"},
+}
+
+// botMarkers flag generic crawlers, scripts and headless browsers. The word bot is
+// matched by hasBotToken instead, since it is also part of device names such as CUBOT.
+var botMarkers = []string{
+	"crawler", "spider", "crawl", "slurp", "headless", "preview",
+	"monitor", "scrapy", "python-urllib", "java/", "okhttp", "axios", "node-fetch",
+	"libwww", "httpclient", "postman",
+}
+
+// browsers are checked in order, since most browsers also claim to be Chrome or Safari.
+var browsers = []family{
+	{"Edge", "Edg/"},
+	{"Edge", "EdgA/"},
+	{"Edge", "EdgiOS/"},
+	{"Opera", "OPR/"},
+	{"Opera", "Opera/"},
+	{"Samsung Internet", "SamsungBrowser/"},
+	{"Yandex Browser", "YaBrowser/"},
+	{"Firefox", "FxiOS/"},
+	{"Firefox", "Firefox/"},
+	{"Chrome", "CriOS/"},
+	{"Chrome", "Chrome/"},
+}
+
+var windowsVersions = map[string]string{
+	"10.0": "10",
+	"6.3":  "8.1",
+	"6.2":  "8",
+	"6.1":  "7",
+	"6.0":  "Vista",
+	"5.2":  "XP",
+	"5.1":  "XP",
+}
+
+// Parse extracts the device type, operating system, browser and bot flag from a User-Agent header.
+// Unknown values are reported as Other, or DeviceUnknown for the device type.
+func Parse(ua string) Info {
+	info := Info{
+		DeviceType:    DeviceUnknown,
+		OSFamily:      Other,
+		BrowserFamily: Other,
+	}
+
+	ua = strings.TrimSpace(ua)
+	if ua == "" {
+		return info
+	}
+
+	info.OSFamily, info.OSVersion = parseOS(ua)
+	info.BrowserFamily, info.BrowserVersion, info.IsBot = parseBrowser(ua)
+	info.DeviceType = parseDevice(ua, info.OSFamily, info.IsBot)
+
+	return info
+}
+
+func parseOS(ua string) (string, string) {
+	switch {
+	case strings.Contains(ua, "Windows Phone"):
+		return "Windows Phone", versionAfter(ua, "Windows Phone ")
+	case strings.Contains(ua, "Windows NT "):
+		return "Windows", windowsVersions[versionAfter(ua, "Windows NT ")]
+	case strings.Contains(ua, "iPhone"), strings.Contains(ua, "iPad"), strings.Contains(ua, "iPod"):
+		return "iOS", versionAfter(ua, " OS ")
+	case strings.Contains(ua, "Mac OS X"):
+		return "macOS", versionAfter(ua, "Mac OS X ")
+	case strings.Contains(ua, "Android"):
+		return "Android", versionAfter(ua, "Android ")
+	case strings.Contains(ua, "CrOS"):
+		return "Chrome OS", ""
+	case strings.Contains(ua, "Linux"), strings.Contains(ua, "X11"):
+		return "Linux", ""
+	}
+	return Other, ""
+}
+
+func parseBrowser(ua string) (string, string, bool) {
+	lower := strings.ToLower(ua)
+
+	for _, bot := range knownBots {
+		if index := strings.Index(lower, bot.token); index >= 0 {
+			return bot.name, versionAfter(lower[index:], "/"), true
+		}
+	}
+	for _, marker := range botMarkers {
+		if strings.Contains(lower, marker) {
+			return Other, "", true
+		}
+	}
+	if hasBotToken(lower) {
+		return Other, "", true
+	}
+
+	for _, browser := range browsers {
+		if strings.Contains(ua, browser.token) {
+			return browser.name, versionAfter(ua, browser.token), false
+		}
+	}
+
+	switch {
+	case strings.Contains(ua, "Safari/") && strings.Contains(ua, "Version/"):
+		return "Safari", versionAfter(ua, "Version/"), false
+	case strings.Contains(ua, "MSIE "):
+		return "Internet Explorer", versionAfter(ua, "MSIE "), false
+	case strings.Contains(ua, "Trident/"):
+		return "Internet Explorer", versionAfter(ua, "rv:"), false
+	}
+	return Other, "", false
+}
+
+// hasBotToken reports whether the lowercase ua has a product token ending in bot, such as
+// SomeBot/1.0 or SomeBot; in a comment, or bot as a word of its own.
+func hasBotToken(lower string) bool {
+	for offset := 0; ; {
+		index := strings.Index(lower[offset:], "bot")
+		if index < 0 {
+			return false
+		}
+		start := offset + index
+		end := start + len("bot")
+		offset = end
+
+		if end == len(lower) {
+			return true
+		}
+		switch lower[end] {
+		case '/', ';', ')', ',', '-':
+			return true
+		}
+		if (start == 0 || !isLetter(lower[start-1])) && !isLetter(lower[end]) {
+			return true
+		}
+	}
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
+
+func parseDevice(ua string, osFamily string, isBot bool) string {
+	switch {
+	case isBot:
+		return DeviceBot
+	case strings.Contains(ua, "iPad"), strings.Contains(ua, "Tablet"),
+		osFamily == "Android" && !strings.Contains(ua, "Mobile"):
+		return DeviceTablet
+	case strings.Contains(ua, "Mobi"), strings.Contains(ua, "iPhone"), strings.Contains(ua, "iPod"),
+		osFamily == "Windows Phone":
+		return DeviceMobile
+	case osFamily == "Windows", osFamily == "macOS", osFamily == "Linux", osFamily == "Chrome OS":
+		return DeviceDesktop
+	}
+	return DeviceUnknown
+}
+
+// versionAfter reads the version that follows token and keeps its major and minor parts.
+// Underscores are accepted as separators, as used by Apple platforms.
+func versionAfter(ua string, token string) string {
+	index := strings.Index(ua, token)
+	if index < 0 {
+		return ""
+	}
+
+	rest := ua[index+len(token):]
+	end := 0
+	for end < len(rest) && (rest[end] >= '0' && rest[end] <= '9' || rest[end] == '.' || rest[end] == '_') {
+		end++
+	}
+
+	parts := strings.FieldsFunc(rest[:end], func(r rune) bool { return r == '.' || r == '_' })
+	if len(parts) > 2 {
+		parts = parts[:2]
+	}
+	return strings.Join(parts, ".")
+}
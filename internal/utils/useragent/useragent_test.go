@@ -0,0 +1,43 @@
+package useragent
+
+import "testing"
+
+func TestParseBots(t *testing.T) {
+	tests := []struct {
+		name    string
+		ua      string
+		isBot   bool
+		browser string
+	}{
+		{name: "googlebot", ua: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", isBot: true, browser: "Googlebot"},
+		{name: "curl", ua: "curl/8.4.0", isBot: true, browser: "curl"},
+		{name: "python requests", ua: "python-requests/2.31.0", isBot: true, browser: "Python Requests"},
+		{name: "link preview", ua: "facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)", isBot: true, browser: "Facebook"},
+		{name: "generic crawler marker", ua: "SomeCrawler/1.0", isBot: true, browser: Other},
+		{name: "headless browser", ua: "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/120.0.0.0 Safari/537.36", isBot: true, browser: Other},
+		{name: "generic bot token", ua: "Mozilla/5.0 (compatible; MJ12bot/v1.4.8; http://mj12bot.com/)", isBot: true, browser: Other},
+		{name: "bot in comment", ua: "Mozilla/5.0 (compatible; PetalBot;+https://webmaster.petalsearch.com/site/petalbot)", isBot: true, browser: Other},
+		{name: "bot as a word", ua: "Uptime bot 2.0", isBot: true, browser: Other},
+		{name: "okhttp", ua: "okhttp/4.12.0", isBot: true, browser: Other},
+		{name: "chrome", ua: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36", isBot: false, browser: "Chrome"},
+		{name: "safari on iphone", ua: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_1 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.1 Mobile/15E148 Safari/604.1", isBot: false, browser: "Safari"},
+		{name: "cubot phone", ua: "Mozilla/5.0 (Linux; Android 9; CUBOT P30) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Mobile Safari/537.36", isBot: false, browser: "Chrome"},
+		{name: "cubot phone with build", ua: "Mozilla/5.0 (Linux; Android 7.0; CUBOT_NOTE_S Build/NRD90M) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/64.0.3282.137 Mobile Safari/537.36", isBot: false, browser: "Chrome"},
+		{name: "empty", ua: "", isBot: false, browser: Other},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := Parse(tt.ua)
+			if info.IsBot != tt.isBot {
+				t.Errorf("Parse(%q).IsBot = %v, want %v", tt.ua, info.IsBot, tt.isBot)
+			}
+			if info.BrowserFamily != tt.browser {
+				t.Errorf("Parse(%q).BrowserFamily = %q, want %q", tt.ua, info.BrowserFamily, tt.browser)
+			}
+			if tt.isBot && info.DeviceType != DeviceBot {
+				t.Errorf("Parse(%q).DeviceType = %q, want %q", tt.ua, info.DeviceType, DeviceBot)
+			}
+		})
+	}
+}
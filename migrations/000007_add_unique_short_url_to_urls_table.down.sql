@@ -0,0 +1,2 @@
+ALTER TABLE
+    "urls" DROP CONSTRAINT IF EXISTS "urls_short_url_unique";
\ No newline at end of file
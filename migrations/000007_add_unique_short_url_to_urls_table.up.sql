@@ -0,0 +1,12 @@
+-- Give every duplicated short url except the oldest one a new code derived from its id,
+-- which is unique, so that the constraint can be added. The owners of the renamed links
+-- can pick a new custom code afterwards.
+UPDATE "urls" SET "short_url" = REPLACE("urls"."id"::TEXT, '-', '')
+    FROM (
+        SELECT "id", ROW_NUMBER() OVER (PARTITION BY "short_url" ORDER BY "created_at", "id") AS "position"
+        FROM "urls"
+    ) "ranked"
+    WHERE "urls"."id" = "ranked"."id" AND "ranked"."position" > 1;
+
+ALTER TABLE
+    "urls" ADD CONSTRAINT "urls_short_url_unique" UNIQUE("short_url");
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE
+    "banned_domains" DROP COLUMN IF EXISTS "rule_type";
\ No newline at end of file
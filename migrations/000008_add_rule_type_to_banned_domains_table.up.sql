@@ -0,0 +1,2 @@
+ALTER TABLE
+    "banned_domains" ADD COLUMN "rule_type" VARCHAR(20) CHECK ("rule_type" IN('exact', 'wildcard', 'path_prefix', 'regex')) NOT NULL DEFAULT 'exact';
\ No newline at end of file
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS banned_domain_scans;
+ALTER TABLE
+    "urls" DROP CONSTRAINT IF EXISTS "urls_blocked_by_id_foreign";
+ALTER TABLE
+    "urls" DROP COLUMN IF EXISTS "blocked_by_id";
+ALTER TABLE
+    "urls" DROP COLUMN IF EXISTS "is_blocked";
\ No newline at end of file
@@ -0,0 +1,25 @@
+ALTER TABLE
+    "urls" ADD COLUMN "is_blocked" BOOLEAN NOT NULL DEFAULT '0';
+ALTER TABLE
+    "urls" ADD COLUMN "blocked_by_id" UUID NULL;
+ALTER TABLE
+    "urls" ADD CONSTRAINT "urls_blocked_by_id_foreign" FOREIGN KEY("blocked_by_id") REFERENCES "banned_domains"("id") ON DELETE SET NULL;
+
+CREATE TABLE "banned_domain_scans"(
+    "id" UUID NOT NULL,
+    "banned_domain_id" UUID NULL,
+    "rule_count" INTEGER NOT NULL DEFAULT 0,
+    "status" VARCHAR(20) CHECK ("status" IN('running', 'completed', 'failed')) NOT NULL DEFAULT 'running',
+    "scanned_count" INTEGER NOT NULL DEFAULT 0,
+    "matched_count" INTEGER NOT NULL DEFAULT 0,
+    "blocked_count" INTEGER NOT NULL DEFAULT 0,
+    "error" TEXT NOT NULL DEFAULT '',
+    "started_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    "finished_at" TIMESTAMP(0) WITHOUT TIME ZONE NULL,
+    "created_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    "updated_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW()
+);
+ALTER TABLE
+    "banned_domain_scans" ADD PRIMARY KEY("id");
+ALTER TABLE
+    "banned_domain_scans" ADD CONSTRAINT "banned_domain_scans_banned_domain_id_foreign" FOREIGN KEY("banned_domain_id") REFERENCES "banned_domains"("id") ON DELETE SET NULL;
\ No newline at end of file
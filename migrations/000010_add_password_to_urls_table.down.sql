@@ -0,0 +1,2 @@
+ALTER TABLE
+    "urls" DROP COLUMN IF EXISTS "password";
\ No newline at end of file
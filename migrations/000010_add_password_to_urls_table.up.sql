@@ -0,0 +1,2 @@
+ALTER TABLE
+    "urls" ADD COLUMN "password" VARCHAR(255) NOT NULL DEFAULT '';
\ No newline at end of file
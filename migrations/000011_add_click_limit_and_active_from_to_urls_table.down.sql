@@ -0,0 +1,6 @@
+ALTER TABLE
+    "urls" DROP COLUMN IF EXISTS "click_count";
+ALTER TABLE
+    "urls" DROP COLUMN IF EXISTS "max_clicks";
+ALTER TABLE
+    "urls" DROP COLUMN IF EXISTS "active_from";
\ No newline at end of file
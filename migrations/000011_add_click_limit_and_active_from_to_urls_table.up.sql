@@ -0,0 +1,6 @@
+ALTER TABLE
+    "urls" ADD COLUMN "active_from" TIMESTAMP(0) WITHOUT TIME ZONE NULL;
+ALTER TABLE
+    "urls" ADD COLUMN "max_clicks" INTEGER NULL CHECK ("max_clicks" > 0);
+ALTER TABLE
+    "urls" ADD COLUMN "click_count" INTEGER NOT NULL DEFAULT 0;
\ No newline at end of file
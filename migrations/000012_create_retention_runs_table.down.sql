@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS "retention_runs";
+DROP INDEX IF EXISTS "urls_expired_at_index";
+ALTER TABLE
+    "urls" DROP COLUMN IF EXISTS "archived_at";
\ No newline at end of file
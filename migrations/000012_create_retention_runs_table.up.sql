@@ -0,0 +1,19 @@
+ALTER TABLE
+    "urls" ADD COLUMN "archived_at" TIMESTAMP(0) WITHOUT TIME ZONE NULL;
+CREATE INDEX "urls_expired_at_index" ON "urls"("expired_at");
+
+CREATE TABLE "retention_runs"(
+    "id" UUID NOT NULL,
+    "mode" VARCHAR(20) CHECK ("mode" IN('delete', 'archive')) NOT NULL,
+    "status" VARCHAR(20) CHECK ("status" IN('running', 'completed', 'failed')) NOT NULL DEFAULT 'running',
+    "cutoff" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL,
+    "batch_count" INTEGER NOT NULL DEFAULT 0,
+    "processed_count" INTEGER NOT NULL DEFAULT 0,
+    "error" TEXT NOT NULL DEFAULT '',
+    "started_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    "finished_at" TIMESTAMP(0) WITHOUT TIME ZONE NULL,
+    "created_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    "updated_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW()
+);
+ALTER TABLE
+    "retention_runs" ADD PRIMARY KEY("id");
\ No newline at end of file
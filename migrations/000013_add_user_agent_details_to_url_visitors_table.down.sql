@@ -0,0 +1,14 @@
+ALTER TABLE
+    "url_visitors" DROP COLUMN IF EXISTS "is_bot";
+ALTER TABLE
+    "url_visitors" DROP COLUMN IF EXISTS "browser_version";
+ALTER TABLE
+    "url_visitors" DROP COLUMN IF EXISTS "browser_family";
+ALTER TABLE
+    "url_visitors" DROP COLUMN IF EXISTS "os_version";
+ALTER TABLE
+    "url_visitors" DROP COLUMN IF EXISTS "os_family";
+ALTER TABLE
+    "url_visitors" DROP COLUMN IF EXISTS "device_type";
+ALTER TABLE
+    "url_visitors" ALTER COLUMN "user_agent" TYPE VARCHAR(255) USING LEFT("user_agent", 255);
\ No newline at end of file
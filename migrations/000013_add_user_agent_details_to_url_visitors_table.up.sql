@@ -0,0 +1,14 @@
+ALTER TABLE
+    "url_visitors" ALTER COLUMN "user_agent" TYPE TEXT;
+ALTER TABLE
+    "url_visitors" ADD COLUMN "device_type" VARCHAR(20) NOT NULL DEFAULT 'unknown';
+ALTER TABLE
+    "url_visitors" ADD COLUMN "os_family" VARCHAR(50) NOT NULL DEFAULT 'Other';
+ALTER TABLE
+    "url_visitors" ADD COLUMN "os_version" VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE
+    "url_visitors" ADD COLUMN "browser_family" VARCHAR(50) NOT NULL DEFAULT 'Other';
+ALTER TABLE
+    "url_visitors" ADD COLUMN "browser_version" VARCHAR(50) NOT NULL DEFAULT '';
+ALTER TABLE
+    "url_visitors" ADD COLUMN "is_bot" BOOLEAN NOT NULL DEFAULT '0';
\ No newline at end of file
@@ -0,0 +1,13 @@
+ALTER TABLE
+    "url_visitors" DROP COLUMN IF EXISTS "ip_hash";
+ALTER TABLE
+    "url_visitors" ALTER COLUMN "ip_address" TYPE VARCHAR(15) USING (
+        CASE
+            WHEN FAMILY("ip_address") = 4 THEN HOST("ip_address")
+            ELSE ''
+        END
+    );
+UPDATE
+    "url_visitors" SET "ip_address" = '' WHERE "ip_address" IS NULL;
+ALTER TABLE
+    "url_visitors" ALTER COLUMN "ip_address" SET NOT NULL;
\ No newline at end of file
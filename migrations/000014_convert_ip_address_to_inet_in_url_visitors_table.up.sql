@@ -0,0 +1,17 @@
+-- Loses data and can't be undone: the addresses that were already stored are converted
+-- whatever VISITOR_IP_MODE is set to, since the mode isn't known to the migration.
+-- IPv4 addresses are truncated to their /24 network, IPv6 and other values are cleared,
+-- and the down migration can't restore either. Rows stored before hash mode is turned on
+-- keep their /24 address and have no ip_hash.
+ALTER TABLE
+    "url_visitors" ALTER COLUMN "ip_address" DROP NOT NULL;
+ALTER TABLE
+    "url_visitors" ALTER COLUMN "ip_address" TYPE INET USING (
+        CASE
+            WHEN "ip_address" ~ '^((25[0-5]|2[0-4][0-9]|1?[0-9]?[0-9])\.){3}(25[0-5]|2[0-4][0-9]|1?[0-9]?[0-9])$'
+            THEN HOST(NETWORK(SET_MASKLEN("ip_address"::INET, 24)))::INET
+            ELSE NULL
+        END
+    );
+ALTER TABLE
+    "url_visitors" ADD COLUMN "ip_hash" VARCHAR(64) NULL;
\ No newline at end of file
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS "urls_user_id_index";
+DROP INDEX IF EXISTS "url_visitors_url_id_created_at_index";
+ALTER TABLE
+    "url_visitors" DROP COLUMN IF EXISTS "country";
+ALTER TABLE
+    "url_visitors" DROP COLUMN IF EXISTS "referrer_host";
\ No newline at end of file
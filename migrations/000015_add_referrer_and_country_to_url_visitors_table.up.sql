@@ -0,0 +1,6 @@
+ALTER TABLE
+    "url_visitors" ADD COLUMN "referrer_host" VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE
+    "url_visitors" ADD COLUMN "country" VARCHAR(2) NOT NULL DEFAULT '';
+CREATE INDEX "url_visitors_url_id_created_at_index" ON "url_visitors"("url_id", "created_at");
+CREATE INDEX "urls_user_id_index" ON "urls"("user_id");
\ No newline at end of file
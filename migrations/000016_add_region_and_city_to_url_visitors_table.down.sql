@@ -0,0 +1,4 @@
+ALTER TABLE
+    "url_visitors" DROP COLUMN IF EXISTS "city";
+ALTER TABLE
+    "url_visitors" DROP COLUMN IF EXISTS "region";
\ No newline at end of file
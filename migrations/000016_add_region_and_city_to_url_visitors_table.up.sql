@@ -0,0 +1,4 @@
+ALTER TABLE
+    "url_visitors" ADD COLUMN "region" VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE
+    "url_visitors" ADD COLUMN "city" VARCHAR(100) NOT NULL DEFAULT '';
\ No newline at end of file
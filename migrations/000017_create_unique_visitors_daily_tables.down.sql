@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "unique_visitors_daily";
+DROP TABLE IF EXISTS "url_unique_visitors_daily";
\ No newline at end of file
@@ -0,0 +1,20 @@
+CREATE TABLE "url_unique_visitors_daily"(
+    "url_id" UUID NOT NULL,
+    "day" DATE NOT NULL,
+    "sketch" BYTEA NOT NULL,
+    "created_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    "updated_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW()
+);
+ALTER TABLE
+    "url_unique_visitors_daily" ADD PRIMARY KEY("url_id", "day");
+ALTER TABLE
+    "url_unique_visitors_daily" ADD CONSTRAINT "url_unique_visitors_daily_url_id_foreign" FOREIGN KEY("url_id") REFERENCES "urls"("id") ON DELETE CASCADE;
+
+CREATE TABLE "unique_visitors_daily"(
+    "day" DATE NOT NULL,
+    "sketch" BYTEA NOT NULL,
+    "created_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    "updated_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW()
+);
+ALTER TABLE
+    "unique_visitors_daily" ADD PRIMARY KEY("day");
\ No newline at end of file
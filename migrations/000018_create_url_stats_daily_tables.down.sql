@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS "url_stats_rollups";
+DROP TABLE IF EXISTS "url_stats_daily";
\ No newline at end of file
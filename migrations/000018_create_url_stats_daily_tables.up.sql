@@ -0,0 +1,23 @@
+CREATE TABLE "url_stats_daily"(
+    "url_id" UUID NOT NULL,
+    "day" DATE NOT NULL,
+    "dimension" VARCHAR(20) NOT NULL,
+    "value" TEXT NOT NULL,
+    "count" BIGINT NOT NULL,
+    "created_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    "updated_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW()
+);
+ALTER TABLE
+    "url_stats_daily" ADD PRIMARY KEY("url_id", "day", "dimension", "value");
+ALTER TABLE
+    "url_stats_daily" ADD CONSTRAINT "url_stats_daily_url_id_foreign" FOREIGN KEY("url_id") REFERENCES "urls"("id") ON DELETE CASCADE;
+CREATE INDEX "url_stats_daily_day_dimension_index" ON "url_stats_daily"("day", "dimension");
+
+CREATE TABLE "url_stats_rollups"(
+    "day" DATE NOT NULL,
+    "visit_count" BIGINT NOT NULL,
+    "created_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    "updated_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW()
+);
+ALTER TABLE
+    "url_stats_rollups" ADD PRIMARY KEY("day");
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS "bot_patterns";
\ No newline at end of file
@@ -0,0 +1,26 @@
+CREATE TABLE "bot_patterns"(
+    "id" UUID NOT NULL,
+    "pattern" VARCHAR(255) NOT NULL,
+    "description" VARCHAR(255) NOT NULL DEFAULT '',
+    "created_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    "updated_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW()
+);
+ALTER TABLE
+    "bot_patterns" ADD PRIMARY KEY("id");
+ALTER TABLE
+    "bot_patterns" ADD CONSTRAINT "bot_patterns_pattern_unique" UNIQUE("pattern");
+
+INSERT INTO "bot_patterns" ("id", "pattern", "description") VALUES
+    (gen_random_uuid(), 'pingdom', 'Pingdom uptime monitoring'),
+    (gen_random_uuid(), 'statuscake', 'StatusCake uptime monitoring'),
+    (gen_random_uuid(), 'site24x7', 'Site24x7 uptime monitoring'),
+    (gen_random_uuid(), 'newrelicpinger', 'New Relic synthetic monitoring'),
+    (gen_random_uuid(), 'datadog', 'Datadog synthetic monitoring'),
+    (gen_random_uuid(), 'check_http', 'Nagios and Icinga HTTP checks'),
+    (gen_random_uuid(), 'zabbix', 'Zabbix web monitoring'),
+    (gen_random_uuid(), 'iframely', 'Iframely link previews'),
+    (gen_random_uuid(), 'embedly', 'Embedly link previews'),
+    (gen_random_uuid(), 'vkshare', 'VK link previews'),
+    (gen_random_uuid(), 'mastodon', 'Mastodon link previews'),
+    (gen_random_uuid(), 'google-inspectiontool', 'Google Search Console inspection'),
+    (gen_random_uuid(), 'chrome-lighthouse', 'Lighthouse and PageSpeed Insights audits');
\ No newline at end of file
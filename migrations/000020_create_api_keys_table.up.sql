@@ -0,0 +1,19 @@
+CREATE TABLE "api_keys"(
+    "id" UUID NOT NULL,
+    "user_id" UUID NOT NULL,
+    "name" VARCHAR(100) NOT NULL,
+    "prefix" VARCHAR(20) NOT NULL,
+    "key_hash" VARCHAR(64) NOT NULL,
+    "scopes" VARCHAR(255) NOT NULL DEFAULT '',
+    "expires_at" TIMESTAMP(0) WITHOUT TIME ZONE NULL,
+    "last_used_at" TIMESTAMP(0) WITHOUT TIME ZONE NULL,
+    "created_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    "updated_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW()
+);
+ALTER TABLE
+    "api_keys" ADD PRIMARY KEY("id");
+ALTER TABLE
+    "api_keys" ADD CONSTRAINT "api_keys_key_hash_unique" UNIQUE("key_hash");
+ALTER TABLE
+    "api_keys" ADD CONSTRAINT "api_keys_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+CREATE INDEX "api_keys_user_id_index" ON "api_keys"("user_id");
\ No newline at end of file
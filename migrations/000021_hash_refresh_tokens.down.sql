@@ -0,0 +1,11 @@
+-- The tokens cannot be recovered from their hashes, every session has to log in again
+DELETE FROM "refresh_tokens";
+
+DROP INDEX IF EXISTS "refresh_tokens_expires_at_index";
+DROP INDEX IF EXISTS "refresh_tokens_family_id_index";
+ALTER TABLE
+    "refresh_tokens" DROP CONSTRAINT IF EXISTS "refresh_tokens_token_hash_unique";
+ALTER TABLE
+    "refresh_tokens" DROP COLUMN IF EXISTS "rotated_at";
+ALTER TABLE
+    "refresh_tokens" DROP COLUMN IF EXISTS "family_id";
\ No newline at end of file
@@ -0,0 +1,21 @@
+-- Tokens that were issued twice in the same second are identical, keep one of them
+DELETE FROM "refresh_tokens" "duplicate" USING "refresh_tokens" "original"
+    WHERE "duplicate"."token_hash" = "original"."token_hash" AND "duplicate"."id" > "original"."id";
+
+ALTER TABLE
+    "refresh_tokens" ADD COLUMN "family_id" UUID NULL;
+ALTER TABLE
+    "refresh_tokens" ADD COLUMN "rotated_at" TIMESTAMP(0) WITHOUT TIME ZONE NULL;
+
+-- Replace the stored tokens with their hash, so that existing sessions keep working,
+-- and start a family for each of them
+UPDATE "refresh_tokens" SET
+    "token_hash" = ENCODE(SHA256(CONVERT_TO("token_hash", 'UTF8')), 'hex'),
+    "family_id" = "id";
+
+ALTER TABLE
+    "refresh_tokens" ALTER COLUMN "family_id" SET NOT NULL;
+ALTER TABLE
+    "refresh_tokens" ADD CONSTRAINT "refresh_tokens_token_hash_unique" UNIQUE("token_hash");
+CREATE INDEX "refresh_tokens_family_id_index" ON "refresh_tokens"("family_id");
+CREATE INDEX "refresh_tokens_expires_at_index" ON "refresh_tokens"("expires_at");
\ No newline at end of file
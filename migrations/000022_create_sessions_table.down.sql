@@ -0,0 +1,4 @@
+ALTER TABLE
+    "refresh_tokens" DROP CONSTRAINT IF EXISTS "refresh_tokens_family_id_foreign";
+
+DROP TABLE IF EXISTS "sessions";
\ No newline at end of file
@@ -0,0 +1,26 @@
+CREATE TABLE "sessions"(
+    "id" UUID NOT NULL,
+    "user_id" UUID NOT NULL,
+    "device" VARCHAR(100) NOT NULL DEFAULT '',
+    "user_agent" TEXT NOT NULL DEFAULT '',
+    "ip_address" INET NULL,
+    "last_used_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    "expires_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL,
+    "created_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW(),
+    "updated_at" TIMESTAMP(0) WITHOUT TIME ZONE NOT NULL DEFAULT NOW()
+);
+ALTER TABLE
+    "sessions" ADD PRIMARY KEY("id");
+ALTER TABLE
+    "sessions" ADD CONSTRAINT "sessions_user_id_foreign" FOREIGN KEY("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+CREATE INDEX "sessions_user_id_index" ON "sessions"("user_id");
+CREATE INDEX "sessions_expires_at_index" ON "sessions"("expires_at");
+
+-- Every refresh token family becomes a session, the device of existing sessions is unknown
+INSERT INTO "sessions" ("id", "user_id", "last_used_at", "expires_at", "created_at")
+    SELECT "family_id", "user_id", MAX("created_at"), TO_TIMESTAMP(MAX("expires_at"))::TIMESTAMP, MIN("created_at")
+    FROM "refresh_tokens"
+    GROUP BY "family_id", "user_id";
+
+ALTER TABLE
+    "refresh_tokens" ADD CONSTRAINT "refresh_tokens_family_id_foreign" FOREIGN KEY("family_id") REFERENCES "sessions"("id") ON DELETE CASCADE;
\ No newline at end of file
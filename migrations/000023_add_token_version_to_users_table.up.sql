@@ -0,0 +1,2 @@
+ALTER TABLE
+    "users" ADD COLUMN "token_version" INTEGER NOT NULL DEFAULT 0;
\ No newline at end of file
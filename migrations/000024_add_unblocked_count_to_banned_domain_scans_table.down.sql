@@ -0,0 +1,2 @@
+ALTER TABLE
+    "banned_domain_scans" DROP COLUMN IF EXISTS "unblocked_count";
\ No newline at end of file
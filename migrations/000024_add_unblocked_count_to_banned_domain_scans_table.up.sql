@@ -0,0 +1,2 @@
+ALTER TABLE
+    "banned_domain_scans" ADD COLUMN "unblocked_count" INTEGER NOT NULL DEFAULT 0;
\ No newline at end of file
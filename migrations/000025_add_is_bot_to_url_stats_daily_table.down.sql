@@ -0,0 +1,7 @@
+TRUNCATE TABLE "url_stats_daily", "url_stats_rollups";
+ALTER TABLE
+    "url_stats_daily" DROP CONSTRAINT "url_stats_daily_pkey";
+ALTER TABLE
+    "url_stats_daily" ADD PRIMARY KEY("url_id", "day", "dimension", "value");
+ALTER TABLE
+    "url_stats_daily" DROP COLUMN IF EXISTS "is_bot";
\ No newline at end of file
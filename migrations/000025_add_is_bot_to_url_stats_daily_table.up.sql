@@ -0,0 +1,8 @@
+-- Split the rollups by the bot flag. They are rebuilt from the raw visits by the next rollup run.
+TRUNCATE TABLE "url_stats_daily", "url_stats_rollups";
+ALTER TABLE
+    "url_stats_daily" ADD COLUMN IF NOT EXISTS "is_bot" BOOLEAN NOT NULL DEFAULT '0';
+ALTER TABLE
+    "url_stats_daily" DROP CONSTRAINT "url_stats_daily_pkey";
+ALTER TABLE
+    "url_stats_daily" ADD PRIMARY KEY("url_id", "day", "dimension", "value", "is_bot");
\ No newline at end of file
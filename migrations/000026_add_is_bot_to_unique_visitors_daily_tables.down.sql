@@ -0,0 +1,15 @@
+DELETE FROM "url_unique_visitors_daily" WHERE "is_bot";
+ALTER TABLE
+    "url_unique_visitors_daily" DROP CONSTRAINT "url_unique_visitors_daily_pkey";
+ALTER TABLE
+    "url_unique_visitors_daily" ADD PRIMARY KEY("url_id", "day");
+ALTER TABLE
+    "url_unique_visitors_daily" DROP COLUMN IF EXISTS "is_bot";
+
+DELETE FROM "unique_visitors_daily" WHERE "is_bot";
+ALTER TABLE
+    "unique_visitors_daily" DROP CONSTRAINT "unique_visitors_daily_pkey";
+ALTER TABLE
+    "unique_visitors_daily" ADD PRIMARY KEY("day");
+ALTER TABLE
+    "unique_visitors_daily" DROP COLUMN IF EXISTS "is_bot";
\ No newline at end of file
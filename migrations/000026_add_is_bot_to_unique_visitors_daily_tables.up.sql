@@ -0,0 +1,15 @@
+-- Bot visits get sketches of their own, so that unique counts can include them. Sketches stored
+-- before this migration only hold human visitors.
+ALTER TABLE
+    "url_unique_visitors_daily" ADD COLUMN "is_bot" BOOLEAN NOT NULL DEFAULT '0';
+ALTER TABLE
+    "url_unique_visitors_daily" DROP CONSTRAINT "url_unique_visitors_daily_pkey";
+ALTER TABLE
+    "url_unique_visitors_daily" ADD PRIMARY KEY("url_id", "day", "is_bot");
+
+ALTER TABLE
+    "unique_visitors_daily" ADD COLUMN "is_bot" BOOLEAN NOT NULL DEFAULT '0';
+ALTER TABLE
+    "unique_visitors_daily" DROP CONSTRAINT "unique_visitors_daily_pkey";
+ALTER TABLE
+    "unique_visitors_daily" ADD PRIMARY KEY("day", "is_bot");
\ No newline at end of file